@@ -30,13 +30,16 @@ func normalizeID(id string) string {
 // It contains basic stats for combat and skill checks.
 // Use NewActor to create instances with the fluent builder API.
 type Actor struct {
-	id              string         // Unique identifier (normalized to lowercase snake_case)
-	maxHP           int            // Maximum Hit Points (base HP)
-	currentHP       int            // Current Hit Points
-	ac              int            // Armor Class (total, including all bonuses)
-	initiative      int            // Initiative order (situational)
-	combatModifiers []Modifier     // Active offensive modifiers for attack rolls
-	attributes      map[string]int // Flexible attribute system (abilities, skills, etc.)
+	id              string                      // Unique identifier (normalized to lowercase snake_case)
+	maxHP           int                         // Maximum Hit Points (base HP)
+	currentHP       int                         // Current Hit Points
+	tempHP          int                         // Temporary Hit Points, lost before current HP
+	ac              int                         // Armor Class (total, including all bonuses)
+	initiative      int                         // Initiative order (situational)
+	combatModifiers []Modifier                  // Active offensive modifiers for attack rolls
+	attributes      map[string]int              // Flexible attribute system (abilities, skills, etc.)
+	damageTraits    map[DamageType]damageTraits // Resistances, vulnerabilities and immunities
+	attacks         []AttackProfile             // Named attacks, in the order added
 }
 
 // ID returns the actor's normalized ID (lowercase snake_case).
@@ -87,17 +90,57 @@ func (a *Actor) SetMaxHP(maxHP int) error {
 	return nil
 }
 
-// SubHP reduces the actor's current HP by the specified amount.
-// HP will not go below 0.
+// SubHP reduces the actor's HP by the specified amount, taking it from
+// temporary HP first. HP will not go below 0. Use ApplyDamage for typed damage.
 func (a *Actor) SubHP(damage int) {
-	a.currentHP -= damage
+	a.takeDamage(damage)
+}
+
+// takeDamage removes damage from temporary HP and then current HP,
+// returning how much the temporary HP absorbed.
+func (a *Actor) takeDamage(damage int) int {
+	absorbed := min(a.tempHP, max(damage, 0))
+	a.tempHP -= absorbed
+	a.currentHP -= damage - absorbed
 	if a.currentHP < 0 {
 		a.currentHP = 0
 	}
+	return absorbed
+}
+
+// TempHP returns the actor's temporary hit points.
+func (a *Actor) TempHP() int {
+	return a.tempHP
+}
+
+// AddTempHP grants temporary hit points. As in D&D 5e, temporary HP don't
+// stack: the actor keeps the higher of its current and the new amount.
+//
+// Example:
+//
+//	actor.AddTempHP(8) // False Life
+//	actor.AddTempHP(5) // Inspiring Leader; the actor keeps 8
+func (a *Actor) AddTempHP(amount int) {
+	a.tempHP = max(a.tempHP, amount)
+}
+
+// SetTempHP sets the actor's temporary hit points directly, replacing any it has.
+// Temporary HP cannot be negative.
+func (a *Actor) SetTempHP(hp int) error {
+	if hp < 0 {
+		return fmt.Errorf("temp hp cannot be negative, got %d", hp)
+	}
+	a.tempHP = hp
+	return nil
+}
+
+// ClearTempHP removes the actor's temporary hit points, e.g. after a long rest.
+func (a *Actor) ClearTempHP() {
+	a.tempHP = 0
 }
 
 // AddHP increases the actor's current HP by the specified amount.
-// HP will not exceed max HP.
+// HP will not exceed max HP. Healing does not restore temporary HP.
 func (a *Actor) AddHP(amount int) {
 	a.currentHP += amount
 	if a.currentHP > a.maxHP {
@@ -203,6 +246,35 @@ func (a *Actor) AddCombatModifier(name string, value int) {
 	a.combatModifiers = append(a.combatModifiers, NewModifier(name, value))
 }
 
+// AddTypedCombatModifier adds a combat modifier with a bonus type, such as a
+// +1 status bonus. Use RollBuilder.WithStacking to stop modifiers of the same
+// type from stacking. The modifier name is automatically lowercased.
+//
+// Example:
+//
+//	actor.AddTypedCombatModifier("heroism", d20.Status, 1)
+//	result, _ := actor.AttackRoll(roller).WithStacking(d20.HighestPerType).Roll()
+func (a *Actor) AddTypedCombatModifier(name string, kind ModifierType, value int) {
+	a.combatModifiers = append(a.combatModifiers, NewTypedModifier(name, kind, value))
+}
+
+// AddCombatDiceModifier adds a modifier that rolls dice on every attack,
+// such as "1d4" for Bless. The modifier name is automatically lowercased.
+// Returns an error if the notation is invalid.
+//
+// Example:
+//
+//	actor.AddCombatDiceModifier("bless", "1d4")
+//	actor.RemoveCombatModifier("bless") // When the spell ends
+func (a *Actor) AddCombatDiceModifier(name string, notation string) error {
+	mod, err := NewDiceModifier(name, notation)
+	if err != nil {
+		return err
+	}
+	a.combatModifiers = append(a.combatModifiers, mod)
+	return nil
+}
+
 // RemoveCombatModifier removes all modifiers with the specified reason.
 // The reason is automatically lowercased for consistent lookups.
 func (a *Actor) RemoveCombatModifier(reason string) {
@@ -227,7 +299,7 @@ func (a *Actor) GetCombatModifiers() []Modifier {
 // SkillCheck creates a RollBuilder for a skill check using D&D 5e conventions (1d20 + skill modifier).
 // The skill value is looked up from the actor's Attributes map.
 // Returns a RollBuilder pre-configured with the skill modifier. Chain .WithAdvantage() or other
-// modifiers as needed, then call .Roll() to execute.
+// modifiers as needed, then call .Roll() to execute, or .Against(dc) to check for success.
 //
 // Returns an error if the skill is not found.
 //
@@ -242,13 +314,14 @@ func (a *Actor) SkillCheck(skill string, roller *Roller) (*RollBuilder, error) {
 	}
 
 	// Return a RollBuilder with skill modifier pre-loaded
-	return roller.Dice(1, 20).WithModifier(skill, skillValue), nil
+	return roller.Dice(1, 20).WithModifier(skill, skillValue).ForActor(a.id).Label(skill), nil
 }
 
 // AttackRoll creates a RollBuilder for an attack roll using the actor's CombatModifiers.
 // Uses D&D 5e conventions (1d20 + combat modifiers).
 // Returns a RollBuilder pre-configured with all combat modifiers. Chain .WithAdvantage(),
-// .WithModifier() for situational bonuses, then call .Roll() to execute.
+// .WithModifier() for situational bonuses, then call .Roll() to execute, or
+// .Against(ac) to check for a hit with natural 20s always hitting and natural 1s missing.
 //
 // Example:
 //
@@ -259,11 +332,11 @@ func (a *Actor) SkillCheck(skill string, roller *Roller) (*RollBuilder, error) {
 //	// With situational modifier
 //	result, _ := actor.AttackRoll(roller).WithModifier("flanking", 2).Roll()
 func (a *Actor) AttackRoll(roller *Roller) *RollBuilder {
-	builder := roller.Dice(1, 20)
+	builder := roller.Dice(1, 20).ForActor(a.id).Label("attack").WithSuccessRule(AttackRollRule())
 
 	// Add all combat modifiers
 	for _, mod := range a.combatModifiers {
-		builder = builder.WithModifier(mod.Reason, mod.Value)
+		builder.modifiers = append(builder.modifiers, mod)
 	}
 
 	return builder
@@ -291,34 +364,41 @@ func (a *Actor) D100SkillCheck(skill string, roller *Roller, bonus int) (bool, R
 
 	var tensDigit, onesDigit int
 
+	roller.mu.Lock()
 	if bonus == 0 {
 		// Normal d100: 1d10 for tens, 1d10 for ones
-		tensDigit = (roller.rng.Intn(10)) * 10
-		onesDigit = roller.rng.Intn(10)
+		tensDigit = (roller.source.IntN(10)) * 10
+		onesDigit = roller.source.IntN(10)
 	} else if bonus > 0 {
 		// Bonus die: Roll (1 + bonus) d10s for tens, take LOWEST
 		rolls := bonus + 1
 		bestTens := 9 // Start with worst
 		for i := 0; i < rolls; i++ {
-			roll := roller.rng.Intn(10)
+			roll := roller.source.IntN(10)
 			if roll < bestTens {
 				bestTens = roll
 			}
 		}
 		tensDigit = bestTens * 10
-		onesDigit = roller.rng.Intn(10)
+		onesDigit = roller.source.IntN(10)
 	} else { // bonus < 0
 		// Penalty die: Roll (1 + |bonus|) d10s for tens, take HIGHEST
 		rolls := -bonus + 1
 		worstTens := 0 // Start with best
 		for i := 0; i < rolls; i++ {
-			roll := roller.rng.Intn(10)
+			roll := roller.source.IntN(10)
 			if roll > worstTens {
 				worstTens = roll
 			}
 		}
 		tensDigit = worstTens * 10
-		onesDigit = roller.rng.Intn(10)
+		onesDigit = roller.source.IntN(10)
+	}
+	err := roller.sourceErr()
+	history := roller.history
+	roller.mu.Unlock()
+	if err != nil {
+		return false, RollOutcome{}, err
 	}
 
 	// Calculate final d100 result (00 = 100)
@@ -337,6 +417,9 @@ func (a *Actor) D100SkillCheck(skill string, roller *Roller, bonus int) (bool, R
 	}
 
 	outcome := NewRollOutcome(1, 100, rolls, modifiers, result)
+	if history != nil {
+		history.record(newHistoryEntry("1d100", outcome, a.id, skill))
+	}
 
 	return success, outcome, nil
 }
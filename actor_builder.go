@@ -3,6 +3,8 @@ package d20
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -11,10 +13,13 @@ import (
 type ActorBuilder struct {
 	id              string
 	maxHP           int
+	tempHP          int
 	ac              int
 	initiative      int
 	combatModifiers []Modifier
 	attributes      map[string]int
+	damageTraits    map[DamageType]damageTraits
+	attacks         []AttackProfile
 	roller          *Roller
 	errors          []error
 }
@@ -37,6 +42,7 @@ func NewActor(id string) *ActorBuilder {
 		initiative:      0, // Default to 0
 		combatModifiers: []Modifier{},
 		attributes:      make(map[string]int),
+		damageTraits:    make(map[DamageType]damageTraits),
 	}
 }
 
@@ -106,9 +112,12 @@ func (ab *ActorBuilder) WithRolledAttribute(key string, roll string) *ActorBuild
 	return ab
 }
 
+// WithRolledAttributes rolls each attribute from its dice notation.
+// Attributes are rolled in sorted key order so seeded rollers give
+// reproducible results.
 func (ab *ActorBuilder) WithRolledAttributes(rolls map[string]string) *ActorBuilder {
-	for key, roll := range rolls {
-		ab.WithRolledAttribute(key, roll)
+	for _, key := range slices.Sorted(maps.Keys(rolls)) {
+		ab.WithRolledAttribute(key, rolls[key])
 	}
 	return ab
 }
@@ -118,6 +127,25 @@ func (ab *ActorBuilder) WithCombatModifier(name string, value int) *ActorBuilder
 	return ab
 }
 
+// WithTypedCombatModifier adds a combat modifier with a bonus type, such as
+// a +1 item bonus from a magic weapon.
+func (ab *ActorBuilder) WithTypedCombatModifier(name string, kind ModifierType, value int) *ActorBuilder {
+	ab.combatModifiers = append(ab.combatModifiers, NewTypedModifier(name, kind, value))
+	return ab
+}
+
+// WithCombatDiceModifier adds a combat modifier that rolls dice on every
+// attack, such as "1d4" for Bless. Invalid notation is reported by Build.
+func (ab *ActorBuilder) WithCombatDiceModifier(name string, notation string) *ActorBuilder {
+	mod, err := NewDiceModifier(name, notation)
+	if err != nil {
+		ab.errors = append(ab.errors, fmt.Errorf("combat modifier %q: %w", name, err))
+		return ab
+	}
+	ab.combatModifiers = append(ab.combatModifiers, mod)
+	return ab
+}
+
 func (ab *ActorBuilder) WithCombatModifiers(mods map[string]int) *ActorBuilder {
 	for name, value := range mods {
 		ab.combatModifiers = append(ab.combatModifiers, NewModifier(name, value))
@@ -125,6 +153,71 @@ func (ab *ActorBuilder) WithCombatModifiers(mods map[string]int) *ActorBuilder {
 	return ab
 }
 
+// WithTempHP gives the actor temporary hit points, which absorb damage
+// before its current HP.
+func (ab *ActorBuilder) WithTempHP(hp int) *ActorBuilder {
+	if hp < 0 {
+		ab.errors = append(ab.errors, fmt.Errorf("temp hp cannot be negative, got %d", hp))
+		return ab
+	}
+	ab.tempHP = hp
+	return ab
+}
+
+// WithResistances makes the actor take half damage from the given types.
+func (ab *ActorBuilder) WithResistances(types ...DamageType) *ActorBuilder {
+	return ab.withDamageTraits(resistant, types)
+}
+
+// WithVulnerabilities makes the actor take double damage from the given types.
+func (ab *ActorBuilder) WithVulnerabilities(types ...DamageType) *ActorBuilder {
+	return ab.withDamageTraits(vulnerable, types)
+}
+
+// WithImmunities makes the actor take no damage from the given types.
+//
+// Example:
+//
+//	elemental, _ := d20.NewActor("fire elemental").
+//	    WithHP(102).
+//	    WithResistances(d20.Bludgeoning, d20.Piercing, d20.Slashing).
+//	    WithImmunities(d20.Fire, d20.Poison).
+//	    Build()
+func (ab *ActorBuilder) WithImmunities(types ...DamageType) *ActorBuilder {
+	return ab.withDamageTraits(immune, types)
+}
+
+func (ab *ActorBuilder) withDamageTraits(trait damageTraits, types []DamageType) *ActorBuilder {
+	for _, t := range types {
+		ab.damageTraits[t.normalize()] |= trait
+	}
+	return ab
+}
+
+// WithAttack adds a named attack profile, such as a bite or a breath weapon.
+// A profile without a name or with invalid damage notation is reported by Build.
+//
+// Example:
+//
+//	wolf, _ := d20.NewActor("wolf").
+//	    WithHP(11).
+//	    WithAttack(d20.AttackProfile{
+//	        Name:        "bite",
+//	        AttackBonus: 4,
+//	        Damage:      []d20.DamageRoll{{Notation: "2d4+2", Type: d20.Piercing}},
+//	    }).
+//	    Build()
+func (ab *ActorBuilder) WithAttack(profile AttackProfile) *ActorBuilder {
+	// Reuse the actor's rules so the builder and AddAttack agree
+	actor := Actor{attacks: ab.attacks}
+	if err := actor.AddAttack(profile); err != nil {
+		ab.errors = append(ab.errors, err)
+		return ab
+	}
+	ab.attacks = actor.attacks
+	return ab
+}
+
 func (ab *ActorBuilder) Build() (*Actor, error) {
 	if ab.maxHP <= 0 {
 		ab.errors = append(ab.errors, fmt.Errorf("hp must be greater than 0, got %d", ab.maxHP))
@@ -138,9 +231,12 @@ func (ab *ActorBuilder) Build() (*Actor, error) {
 		id:              ab.id,
 		maxHP:           ab.maxHP,
 		currentHP:       ab.maxHP,
+		tempHP:          ab.tempHP,
 		ac:              ab.ac,
 		initiative:      ab.initiative,
 		combatModifiers: ab.combatModifiers,
 		attributes:      ab.attributes,
+		damageTraits:    ab.damageTraits,
+		attacks:         ab.attacks,
 	}, nil
 }
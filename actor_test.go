@@ -1,6 +1,7 @@
 package d20
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -235,6 +236,56 @@ func TestActor_SubHP(t *testing.T) {
 	}
 }
 
+// Test Actor temporary HP
+func TestActor_TempHP(t *testing.T) {
+	actor, err := NewActor("hero").
+		WithHP(20).
+		WithTempHP(5).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	// Temp HP absorb damage first
+	actor.SubHP(3)
+	if actor.TempHP() != 2 || actor.HP() != 20 {
+		t.Errorf("Expected 20 HP and 2 temp HP, got %d and %d", actor.HP(), actor.TempHP())
+	}
+	actor.SubHP(6)
+	if actor.TempHP() != 0 || actor.HP() != 16 {
+		t.Errorf("Expected 16 HP and 0 temp HP, got %d and %d", actor.HP(), actor.TempHP())
+	}
+
+	// Healing does not restore temp HP
+	actor.AddHP(10)
+	if actor.TempHP() != 0 || actor.HP() != 20 {
+		t.Errorf("Expected 20 HP and 0 temp HP after healing, got %d and %d", actor.HP(), actor.TempHP())
+	}
+
+	// Temp HP don't stack
+	actor.AddTempHP(8)
+	actor.AddTempHP(5)
+	if actor.TempHP() != 8 {
+		t.Errorf("Expected the higher 8 temp HP, got %d", actor.TempHP())
+	}
+
+	if err := actor.SetTempHP(-1); err == nil {
+		t.Error("SetTempHP() should reject negative temp HP")
+	}
+	_ = actor.SetTempHP(3)
+	if actor.TempHP() != 3 {
+		t.Errorf("Expected 3 temp HP, got %d", actor.TempHP())
+	}
+	actor.ClearTempHP()
+	if actor.TempHP() != 0 {
+		t.Errorf("Expected no temp HP after clearing, got %d", actor.TempHP())
+	}
+
+	if _, err := NewActor("hero").WithHP(20).WithTempHP(-1).Build(); err == nil {
+		t.Error("Build() should reject negative temp HP")
+	}
+}
+
 // Test Actor.AddHP
 func TestActor_AddHP(t *testing.T) {
 	actor, _ := NewActor("hero").
@@ -433,6 +484,52 @@ func TestActor_AddCombatModifier(t *testing.T) {
 	}
 }
 
+func TestActor_CombatDiceModifier(t *testing.T) {
+	actor, err := NewActor("cleric").
+		WithHP(20).
+		WithCombatModifier("strength", 2).
+		WithCombatDiceModifier("bless", "1d4").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	if err := actor.AddCombatDiceModifier("inspiration", "1d8"); err != nil {
+		t.Fatalf("AddCombatDiceModifier() error: %v", err)
+	}
+
+	roller := NewScriptedRoller(NewScript(10, 3, 6))
+	result, err := actor.AttackRoll(roller).Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if result.Value != 21 {
+		t.Errorf("Value = %d, want 21", result.Value)
+	}
+	if !strings.Contains(result.Detail, "+2 strength, +1d4 (3) bless, +1d8 (6) inspiration") {
+		t.Errorf("Detail %q should show the rolled buffs", result.Detail)
+	}
+
+	actor.RemoveCombatModifier("bless")
+	if mods := actor.GetCombatModifiers(); len(mods) != 2 {
+		t.Errorf("Expected 2 combat modifiers after removing bless, got %d", len(mods))
+	}
+
+	if err := actor.AddCombatDiceModifier("bless", "d"); err == nil {
+		t.Error("AddCombatDiceModifier() should reject invalid notation")
+	}
+	if _, err := NewActor("cleric").WithHP(20).WithCombatDiceModifier("bless", "d").Build(); err == nil {
+		t.Error("Build() should reject invalid dice modifier notation")
+	}
+
+	// Notation is case-insensitive, as in Roller.Notation
+	if err := actor.AddCombatDiceModifier("guidance", "1D4"); err != nil {
+		t.Errorf("AddCombatDiceModifier() error: %v", err)
+	}
+	if _, err := NewActor("cleric").WithHP(20).WithCombatDiceModifier("bless", "1D4").Build(); err != nil {
+		t.Errorf("Build() error: %v", err)
+	}
+}
+
 // Test Actor.RemoveCombatModifier
 func TestActor_RemoveCombatModifier(t *testing.T) {
 	actor, _ := NewActor("hero").
@@ -1,5 +1,7 @@
 package d20
 
+import "strings"
+
 // AdvantageType represents the advantage/disadvantage state for a roll.
 // This is a core 5e mechanic where advantage means roll twice and take higher,
 // disadvantage means roll twice and take lower.
@@ -10,3 +12,105 @@ const (
 	Normal                            // Roll normally
 	Advantage                         // Roll twice, take higher
 )
+
+// String returns "advantage", "disadvantage" or "normal".
+func (a AdvantageType) String() string {
+	switch a {
+	case Advantage:
+		return "advantage"
+	case Disadvantage:
+		return "disadvantage"
+	}
+	return "normal"
+}
+
+// AdvantageSource is one reason a roll has advantage or disadvantage,
+// such as "flanking" or "poisoned".
+type AdvantageSource struct {
+	Type   AdvantageType // Advantage or Disadvantage
+	Reason string        // Why the roll has it; may be empty
+}
+
+// AdvantagePolicy resolves a roll's advantage and disadvantage sources into
+// the way it is rolled.
+type AdvantagePolicy func(sources []AdvantageSource) AdvantageType
+
+// CancelAdvantage resolves sources by D&D 5e rules: any advantage and any
+// disadvantage cancel to a normal roll, however many of each there are.
+// This is the default policy.
+func CancelAdvantage(sources []AdvantageSource) AdvantageType {
+	advantage, disadvantage := countAdvantage(sources)
+	switch {
+	case advantage > 0 && disadvantage == 0:
+		return Advantage
+	case disadvantage > 0 && advantage == 0:
+		return Disadvantage
+	}
+	return Normal
+}
+
+// NetAdvantage resolves sources by counting them: the roll has advantage if
+// it has more advantage sources than disadvantage sources, and vice versa.
+func NetAdvantage(sources []AdvantageSource) AdvantageType {
+	advantage, disadvantage := countAdvantage(sources)
+	switch {
+	case advantage > disadvantage:
+		return Advantage
+	case disadvantage > advantage:
+		return Disadvantage
+	}
+	return Normal
+}
+
+// countAdvantage counts the advantage and disadvantage sources.
+func countAdvantage(sources []AdvantageSource) (advantage, disadvantage int) {
+	for _, source := range sources {
+		switch source.Type {
+		case Advantage:
+			advantage++
+		case Disadvantage:
+			disadvantage++
+		}
+	}
+	return advantage, disadvantage
+}
+
+// advantageNote describes the sources of a roll's advantage and how they
+// resolved, e.g. "Advantage (flanking) and disadvantage (poisoned): normal".
+// Returns "" for a single unnamed source, which the dice already show.
+func advantageNote(sources []AdvantageSource, resolved AdvantageType) string {
+	var parts []string
+	named := false
+	for _, kind := range []AdvantageType{Advantage, Disadvantage} {
+		var reasons []string
+		found := false
+		for _, source := range sources {
+			if source.Type != kind {
+				continue
+			}
+			found = true
+			if source.Reason != "" {
+				reasons = append(reasons, source.Reason)
+			}
+		}
+		if !found {
+			continue
+		}
+		part := kind.String()
+		if len(reasons) > 0 {
+			part += " (" + strings.Join(reasons, ", ") + ")"
+			named = true
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 || (len(parts) == 1 && !named) {
+		return ""
+	}
+	note := strings.Join(parts, " and ")
+	note = strings.ToUpper(note[:1]) + note[1:]
+	if len(parts) > 1 {
+		note += ": " + resolved.String()
+	}
+	return note
+}
@@ -0,0 +1,147 @@
+package d20
+
+import (
+	"errors"
+	"strings"
+)
+
+var errNoTarget = errors.New("attack needs a target")
+
+// AttackResult is the outcome of an attack resolved against a target.
+type AttackResult struct {
+	Attack   CheckResult   // Attack roll against the target's AC
+	Hit      bool          // Whether the attack hit; natural 20s always hit and natural 1s miss
+	Critical bool          // Whether the attack was a critical hit
+	Damage   []RollOutcome // Damage rolled for each of the profile's components; empty on a miss
+	Report   DamageReport  // Damage dealt after the target's resistances; zero on a miss
+	HPBefore int           // Target's HP before the attack
+	HPAfter  int           // Target's HP after the damage, or what it would be if not applied
+	Applied  bool          // Whether the damage was applied to the target
+	Detail   string        // One line per roll, headed by a summary, e.g. "fighter attacks goblin with longsword: *Hit!*"
+}
+
+// AttackBuilder configures an attack against a target before resolving it.
+// Create one with Actor.Attack.
+type AttackBuilder struct {
+	attacker *Actor
+	target   *Actor
+	profile  AttackProfile
+	roller   *Roller
+	attack   *RollBuilder
+	dryRun   bool
+}
+
+// Attack starts an attack against a target with the given attack profile.
+// The attack roll includes the attacker's combat modifiers and the profile's
+// attack bonus. Call Resolve to roll the attack and, on a hit, the damage.
+//
+// Example:
+//
+//	result, _ := fighter.Attack(goblin, longsword, roller).WithAdvantage("flanking").Resolve()
+//	fmt.Println(result.Detail)
+func (a *Actor) Attack(target *Actor, profile AttackProfile, roller *Roller) *AttackBuilder {
+	return &AttackBuilder{
+		attacker: a,
+		target:   target,
+		profile:  profile,
+		roller:   roller,
+		attack:   profile.attackRoll(a, roller),
+	}
+}
+
+// WithAdvantage gives the attack roll advantage, with optional reasons.
+func (ab *AttackBuilder) WithAdvantage(reasons ...string) *AttackBuilder {
+	ab.attack.WithAdvantage(reasons...)
+	return ab
+}
+
+// WithDisadvantage gives the attack roll disadvantage, with optional reasons.
+func (ab *AttackBuilder) WithDisadvantage(reasons ...string) *AttackBuilder {
+	ab.attack.WithDisadvantage(reasons...)
+	return ab
+}
+
+// WithModifier adds a situational modifier to the attack roll.
+func (ab *AttackBuilder) WithModifier(name string, value int) *AttackBuilder {
+	ab.attack.WithModifier(name, value)
+	return ab
+}
+
+// DryRun resolves the attack without applying damage to the target.
+// The result still reports the damage and the HP the target would be left with.
+func (ab *AttackBuilder) DryRun() *AttackBuilder {
+	ab.dryRun = true
+	return ab
+}
+
+// Resolve rolls the attack against the target's AC and, on a hit, rolls each
+// damage component, doubling dice on a critical hit, and applies the damage
+// through the target's resistances, vulnerabilities and temporary HP.
+// Returns an error if there is no target or a damage notation is invalid.
+func (ab *AttackBuilder) Resolve() (AttackResult, error) {
+	if ab.target == nil {
+		return AttackResult{}, errNoTarget
+	}
+	// Check damage before rolling so an invalid profile consumes no dice
+	if err := ab.profile.validate(); err != nil {
+		return AttackResult{}, err
+	}
+
+	attack, err := ab.attack.Against(ab.target.AC())
+	if err != nil {
+		return AttackResult{}, err
+	}
+	result := AttackResult{
+		Attack:   attack,
+		Hit:      attack.Success,
+		Critical: attack.Success && attack.IsCritical,
+		HPBefore: ab.target.HP(),
+		HPAfter:  ab.target.HP(),
+		Applied:  !ab.dryRun,
+	}
+
+	lines := []string{ab.summary(result), attack.Detail}
+	if result.Hit {
+		builders, err := ab.profile.damageRolls(ab.attacker, ab.roller, result.Critical)
+		if err != nil {
+			return AttackResult{}, err
+		}
+		components, outcomes, err := ab.profile.rollDamage(builders)
+		if err != nil {
+			return AttackResult{}, err
+		}
+		result.Damage = outcomes
+		for _, outcome := range outcomes {
+			lines = append(lines, outcome.Detail)
+		}
+
+		// A dry run applies the damage to a copy of the target
+		target := ab.target
+		if ab.dryRun {
+			preview := *ab.target
+			target = &preview
+		}
+		result.Report = target.ApplyDamage(components...)
+		result.HPAfter = result.Report.HP
+		lines = append(lines, result.Report.Detail)
+	}
+
+	result.Detail = strings.Join(lines, "\n")
+	return result, nil
+}
+
+// summary describes who attacked whom and how it went,
+// e.g. "fighter attacks goblin with longsword: *Critical hit!*".
+func (ab *AttackBuilder) summary(result AttackResult) string {
+	s := ab.attacker.id + " attacks " + ab.target.id
+	if ab.profile.Name != "" {
+		s += " with " + ab.profile.Name
+	}
+	switch {
+	case result.Critical:
+		return s + ": *Critical hit!*"
+	case result.Hit:
+		return s + ": *Hit!*"
+	}
+	return s + ": *Miss!*"
+}
@@ -0,0 +1,251 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DamageRoll is one typed damage component of an attack, such as "1d8+3"
+// slashing.
+type DamageRoll struct {
+	Notation string     // Dice notation, e.g. "1d8+3"
+	Type     DamageType // Damage type; may be empty for untyped damage
+}
+
+// AttackProfile describes a weapon or attack an actor can make.
+//
+// Example:
+//
+//	flameTongue := d20.AttackProfile{
+//		Name:        "flame tongue",
+//		AttackBonus: 1,
+//		Damage: []d20.DamageRoll{
+//			{Notation: "1d8+4", Type: d20.Slashing},
+//			{Notation: "2d6", Type: d20.Fire},
+//		},
+//	}
+type AttackProfile struct {
+	Name            string       // Name shown in the detail and used to look the profile up, e.g. "longsword"
+	AttackBonus     int          // Added to the attack roll on top of combat modifiers, e.g. 1 for a +1 weapon
+	AttackModifiers []Modifier   // Further named to-hit modifiers for this attack only
+	Damage          []DamageRoll // Damage components rolled on a hit
+	CritRange       int          // Lowest natural roll that crits; 0 means a natural 20
+	Critical        CritDamage   // Damage scaling on a critical hit; NormalDamage means DoubleDice
+	Reach           int          // Melee reach in feet; informational
+	Range           int          // Normal range in feet for ranged attacks; informational
+	LongRange       int          // Long range in feet for ranged attacks; informational
+}
+
+// validate checks that the profile's damage notation can be rolled, using
+// the same rules as Roller.Notation so errors surface before any dice are rolled.
+func (p AttackProfile) validate() error {
+	for _, component := range p.Damage {
+		// Parsing never rolls dice, so the roller needs no random source
+		if _, err := (&Roller{}).Notation(component.Notation); err != nil {
+			return fmt.Errorf("attack %q damage %q: %w", p.Name, component.Notation, err)
+		}
+	}
+	return nil
+}
+
+// attackRoll creates the attack roll for the profile: the actor's combat
+// modifiers plus the profile's bonus, modifiers and crit range.
+func (p AttackProfile) attackRoll(a *Actor, roller *Roller) *RollBuilder {
+	attack := a.AttackRoll(roller)
+	if p.AttackBonus != 0 {
+		attack = attack.WithModifier(p.Name, p.AttackBonus)
+	}
+	attack = attack.ApplyModifiers(p.AttackModifiers...)
+	if p.CritRange != 0 {
+		attack = attack.CritRange(p.CritRange)
+	}
+	if p.Name != "" {
+		attack = attack.Label(p.Name + " attack")
+	}
+	return attack
+}
+
+// damageRolls creates a roll builder for each of the profile's damage
+// components, scaled for a critical hit if critical is true.
+func (p AttackProfile) damageRolls(a *Actor, roller *Roller, critical bool) ([]*RollBuilder, error) {
+	mode := p.Critical
+	if mode == NormalDamage {
+		mode = DoubleDice
+	}
+
+	builders := make([]*RollBuilder, len(p.Damage))
+	for i, component := range p.Damage {
+		builder, err := roller.Notation(component.Notation)
+		if err != nil {
+			return nil, fmt.Errorf("attack %q damage %q: %w", p.Name, component.Notation, err)
+		}
+		builder = builder.ForActor(a.id).Label(strings.TrimSpace(p.Name + " damage"))
+		if critical {
+			builder = builder.CriticalDamage(mode)
+		}
+		builders[i] = builder
+	}
+	return builders, nil
+}
+
+// rollDamage rolls the damage builders, returning the typed components
+// ready for ApplyDamage along with each roll.
+func (p AttackProfile) rollDamage(builders []*RollBuilder) ([]Damage, []RollOutcome, error) {
+	damage := make([]Damage, len(builders))
+	outcomes := make([]RollOutcome, len(builders))
+	for i, builder := range builders {
+		outcome, err := builder.Roll()
+		if err != nil {
+			return nil, nil, err
+		}
+		damage[i] = Damage{Amount: outcome.Value, Type: p.Damage[i].Type}
+		outcomes[i] = outcome
+	}
+	return damage, outcomes, nil
+}
+
+// clone returns a copy of the profile that shares no slices with the original.
+func (p AttackProfile) clone() AttackProfile {
+	p.AttackModifiers = slices.Clone(p.AttackModifiers)
+	p.Damage = slices.Clone(p.Damage)
+	return p
+}
+
+// normalizeAttackName lowercases an attack name for consistent lookups.
+func normalizeAttackName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// AddAttack gives the actor a named attack profile, such as a bite or a
+// breath weapon, replacing any profile with the same name.
+// The name is automatically lowercased. Returns an error if the profile
+// has no name or its damage notation is invalid.
+//
+// Example:
+//
+//	wolf.AddAttack(d20.AttackProfile{
+//		Name:        "bite",
+//		AttackBonus: 4,
+//		Damage:      []d20.DamageRoll{{Notation: "2d4+2", Type: d20.Piercing}},
+//		Reach:       5,
+//	})
+func (a *Actor) AddAttack(profile AttackProfile) error {
+	profile = profile.clone()
+	profile.Name = normalizeAttackName(profile.Name)
+	if profile.Name == "" {
+		return errors.New("attack profile needs a name")
+	}
+	if err := profile.validate(); err != nil {
+		return err
+	}
+
+	for i, existing := range a.attacks {
+		if existing.Name == profile.Name {
+			a.attacks[i] = profile
+			return nil
+		}
+	}
+	a.attacks = append(a.attacks, profile)
+	return nil
+}
+
+// RemoveAttack removes the named attack profile.
+func (a *Actor) RemoveAttack(name string) {
+	name = normalizeAttackName(name)
+	a.attacks = slices.DeleteFunc(a.attacks, func(p AttackProfile) bool {
+		return p.Name == name
+	})
+}
+
+// AttackProfile returns a copy of the named attack profile.
+func (a *Actor) AttackProfile(name string) (AttackProfile, bool) {
+	name = normalizeAttackName(name)
+	for _, profile := range a.attacks {
+		if profile.Name == name {
+			return profile.clone(), true
+		}
+	}
+	return AttackProfile{}, false
+}
+
+// Attacks returns copies of the actor's attack profiles in the order they were added.
+func (a *Actor) Attacks() []AttackProfile {
+	profiles := make([]AttackProfile, len(a.attacks))
+	for i, profile := range a.attacks {
+		profiles[i] = profile.clone()
+	}
+	return profiles
+}
+
+// profile looks up a named attack profile, returning an error if the actor
+// has none by that name.
+func (a *Actor) profile(name string) (AttackProfile, error) {
+	profile, ok := a.AttackProfile(name)
+	if !ok {
+		return AttackProfile{}, fmt.Errorf("attack %q not found for actor %q", normalizeAttackName(name), a.id)
+	}
+	return profile, nil
+}
+
+// AttackRollWith creates a RollBuilder for an attack roll with one of the
+// actor's named attack profiles: the actor's combat modifiers plus the
+// profile's to-hit modifiers and crit range. AttackRoll is unchanged and
+// still uses only the combat modifiers.
+//
+// Returns an error if the actor has no attack by that name.
+//
+// Example:
+//
+//	attack, _ := dragon.AttackRollWith("claw", roller)
+//	check, _ := attack.Against(target.AC())
+func (a *Actor) AttackRollWith(name string, roller *Roller) (*RollBuilder, error) {
+	profile, err := a.profile(name)
+	if err != nil {
+		return nil, err
+	}
+	return profile.attackRoll(a, roller), nil
+}
+
+// RollDamage rolls the damage of one of the actor's named attack profiles,
+// scaled by the profile's crit mode if critical is true. Returns the typed
+// damage components, ready for ApplyDamage, and the roll for each.
+//
+// Returns an error if the actor has no attack by that name.
+//
+// Example:
+//
+//	damage, _, _ := dragon.RollDamage("bite", roller, check.IsCritical)
+//	report := target.ApplyDamage(damage...)
+func (a *Actor) RollDamage(name string, roller *Roller, critical bool) ([]Damage, []RollOutcome, error) {
+	profile, err := a.profile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	builders, err := profile.damageRolls(a, roller, critical)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profile.rollDamage(builders)
+}
+
+// AttackWith starts an attack against a target with one of the actor's
+// named attack profiles. See Attack.
+//
+// Returns an error if the actor has no attack by that name.
+//
+// Example:
+//
+//	for _, name := range []string{"bite", "claw", "claw"} {
+//		builder, _ := dragon.AttackWith(name, fighter, roller)
+//		result, _ := builder.Resolve()
+//		fmt.Println(result.Detail)
+//	}
+func (a *Actor) AttackWith(name string, target *Actor, roller *Roller) (*AttackBuilder, error) {
+	profile, err := a.profile(name)
+	if err != nil {
+		return nil, err
+	}
+	return a.Attack(target, profile, roller), nil
+}
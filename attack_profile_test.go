@@ -0,0 +1,156 @@
+package d20
+
+import (
+	"strings"
+	"testing"
+)
+
+func newWolf(t *testing.T) *Actor {
+	t.Helper()
+	wolf, err := NewActor("dire wolf").
+		WithHP(37).
+		WithCombatModifier("strength", 3).
+		WithAttack(AttackProfile{
+			Name:        "Bite",
+			AttackBonus: 2,
+			Damage:      []DamageRoll{{Notation: "2d6+3", Type: Piercing}},
+			Reach:       5,
+		}).
+		WithAttack(AttackProfile{
+			Name:            "claw",
+			AttackModifiers: []Modifier{NewModifier("pack tactics", 1)},
+			Damage:          []DamageRoll{{Notation: "1d6+3", Type: Slashing}},
+			CritRange:       19,
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	return wolf
+}
+
+func TestActor_AttackProfiles(t *testing.T) {
+	wolf := newWolf(t)
+
+	attacks := wolf.Attacks()
+	if len(attacks) != 2 || attacks[0].Name != "bite" || attacks[1].Name != "claw" {
+		t.Fatalf("Attacks() = %v, want bite then claw", attacks)
+	}
+
+	bite, ok := wolf.AttackProfile("BITE")
+	if !ok || bite.Reach != 5 {
+		t.Errorf("AttackProfile(%q) = %+v, %v; want the bite", "BITE", bite, ok)
+	}
+	bite.Damage[0].Notation = "1d100"
+	if again, _ := wolf.AttackProfile("bite"); again.Damage[0].Notation != "2d6+3" {
+		t.Error("AttackProfile() should return a copy")
+	}
+
+	if err := wolf.AddAttack(AttackProfile{Name: "bite", Damage: []DamageRoll{{Notation: "3d6", Type: Piercing}}}); err != nil {
+		t.Fatalf("AddAttack() error: %v", err)
+	}
+	if bite, _ := wolf.AttackProfile("bite"); len(wolf.Attacks()) != 2 || bite.Damage[0].Notation != "3d6" {
+		t.Error("AddAttack() should replace a profile with the same name")
+	}
+
+	wolf.RemoveAttack("Claw")
+	if _, ok := wolf.AttackProfile("claw"); ok {
+		t.Error("RemoveAttack() should remove the profile")
+	}
+
+	if err := wolf.AddAttack(AttackProfile{}); err == nil {
+		t.Error("AddAttack() should require a name")
+	}
+	if err := wolf.AddAttack(AttackProfile{Name: "breath", Damage: []DamageRoll{{Notation: "d"}}}); err == nil {
+		t.Error("AddAttack() should reject invalid damage notation")
+	}
+	if err := wolf.AddAttack(AttackProfile{Name: "slam", Damage: []DamageRoll{{Notation: "5"}}}); err == nil {
+		t.Error("AddAttack() should reject damage notation without dice")
+	}
+	if err := wolf.AddAttack(AttackProfile{Name: "slam", Damage: []DamageRoll{{Notation: "1D8+3"}}}); err != nil {
+		t.Errorf("AddAttack() should accept uppercase notation, got %v", err)
+	}
+	if _, err := NewActor("wolf").WithHP(11).WithAttack(AttackProfile{Name: "bite", Damage: []DamageRoll{{Notation: "d"}}}).Build(); err == nil {
+		t.Error("Build() should reject invalid attack profiles")
+	}
+}
+
+func TestActor_AttackRollWith(t *testing.T) {
+	wolf := newWolf(t)
+	roller := NewScriptedRoller(NewScript(10, 19, 10))
+
+	bite, err := wolf.AttackRollWith("bite", roller)
+	if err != nil {
+		t.Fatalf("AttackRollWith() error: %v", err)
+	}
+	result, _ := bite.Roll()
+	if result.Value != 15 || !strings.Contains(result.Detail, "+3 strength, +2 bite") {
+		t.Errorf("bite Value = %d, Detail = %q; want 15 with strength and bite bonuses", result.Value, result.Detail)
+	}
+
+	claw, _ := wolf.AttackRollWith("claw", roller)
+	if result, _ := claw.Roll(); !result.IsCritical || result.Value != 23 {
+		t.Errorf("claw Value = %d, IsCritical = %v; want 23 and a crit on 19", result.Value, result.IsCritical)
+	}
+
+	// AttackRoll keeps using only the combat modifiers
+	if result, _ := wolf.AttackRoll(roller).Roll(); result.Value != 13 {
+		t.Errorf("AttackRoll() Value = %d, want 13", result.Value)
+	}
+
+	if _, err := wolf.AttackRollWith("breath", roller); err == nil {
+		t.Error("AttackRollWith() should reject unknown attacks")
+	}
+}
+
+func TestActor_RollDamage(t *testing.T) {
+	wolf := newWolf(t)
+	roller := NewScriptedRoller(NewScript(4, 2, 4, 2, 6, 1))
+
+	damage, outcomes, err := wolf.RollDamage("bite", roller, false)
+	if err != nil {
+		t.Fatalf("RollDamage() error: %v", err)
+	}
+	if len(damage) != 1 || damage[0] != (Damage{Amount: 9, Type: Piercing}) || outcomes[0].Value != 9 {
+		t.Errorf("damage = %+v, want 9 piercing", damage)
+	}
+
+	damage, _, err = wolf.RollDamage("bite", roller, true)
+	if err != nil {
+		t.Fatalf("RollDamage() error: %v", err)
+	}
+	if damage[0].Amount != 16 {
+		t.Errorf("critical damage = %d, want 16", damage[0].Amount)
+	}
+
+	if _, _, err := wolf.RollDamage("breath", roller, false); err == nil {
+		t.Error("RollDamage() should reject unknown attacks")
+	}
+}
+
+func TestActor_AttackWith(t *testing.T) {
+	wolf := newWolf(t)
+	target, _ := NewActor("hunter").WithHP(20).WithAC(12).Build()
+	roller := NewScriptedRoller(NewScript(10, 4, 2, 15, 5))
+
+	for _, name := range []string{"bite", "claw"} {
+		builder, err := wolf.AttackWith(name, target, roller)
+		if err != nil {
+			t.Fatalf("AttackWith(%q) error: %v", name, err)
+		}
+		result, err := builder.Resolve()
+		if err != nil {
+			t.Fatalf("Resolve() error: %v", err)
+		}
+		if !result.Hit {
+			t.Errorf("%s should hit AC 12", name)
+		}
+	}
+	if target.HP() != 3 {
+		t.Errorf("target HP = %d, want 3", target.HP())
+	}
+
+	if _, err := wolf.AttackWith("breath", target, roller); err == nil {
+		t.Error("AttackWith() should reject unknown attacks")
+	}
+}
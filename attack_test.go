@@ -0,0 +1,145 @@
+package d20
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActor_Attack(t *testing.T) {
+	longsword := AttackProfile{
+		Name:        "longsword",
+		AttackBonus: 1,
+		Damage: []DamageRoll{
+			{Notation: "1d8+3", Type: Slashing},
+			{Notation: "1d6", Type: Fire},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		script       []int
+		profile      AttackProfile
+		dryRun       bool
+		wantHit      bool
+		wantCritical bool
+		wantDamage   int
+		wantHPAfter  int
+	}{
+		{name: "Hit", script: []int{10, 5, 4}, profile: longsword, wantHit: true, wantDamage: 10, wantHPAfter: 20},
+		{name: "Miss", script: []int{5}, profile: longsword, wantHit: false, wantDamage: 0, wantHPAfter: 30},
+		{name: "Natural 1 misses", script: []int{1}, profile: AttackProfile{Name: "dagger", AttackBonus: 20}, wantHit: false, wantHPAfter: 30},
+		{name: "Critical doubles dice", script: []int{20, 5, 4, 4, 2}, profile: longsword, wantHit: true, wantCritical: true, wantDamage: 15, wantHPAfter: 15},
+		{
+			name:         "Crit range",
+			script:       []int{19, 5, 4, 4, 2},
+			profile:      AttackProfile{Name: "scimitar", CritRange: 19, Damage: longsword.Damage},
+			wantHit:      true,
+			wantCritical: true,
+			wantDamage:   15,
+			wantHPAfter:  15,
+		},
+		{
+			name:         "Critical mode",
+			script:       []int{20, 5, 4},
+			profile:      AttackProfile{Name: "glaive", Critical: DoubleTotal, Damage: longsword.Damage},
+			wantHit:      true,
+			wantCritical: true,
+			wantDamage:   20,
+			wantHPAfter:  10,
+		},
+		{name: "Dry run", script: []int{10, 5, 4}, profile: longsword, dryRun: true, wantHit: true, wantDamage: 10, wantHPAfter: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fighter, _ := NewActor("fighter").WithHP(40).WithCombatModifier("strength", 3).Build()
+			goblin, _ := NewActor("goblin").WithHP(30).WithAC(14).WithResistances(Fire).Build()
+			roller := NewScriptedRoller(NewScript(tt.script...))
+
+			builder := fighter.Attack(goblin, tt.profile, roller)
+			if tt.dryRun {
+				builder = builder.DryRun()
+			}
+			result, err := builder.Resolve()
+			if err != nil {
+				t.Fatalf("Resolve() error: %v", err)
+			}
+			if result.Hit != tt.wantHit || result.Critical != tt.wantCritical {
+				t.Errorf("Hit = %v, Critical = %v; want %v, %v", result.Hit, result.Critical, tt.wantHit, tt.wantCritical)
+			}
+			if result.Report.Total != tt.wantDamage {
+				t.Errorf("damage dealt = %d, want %d", result.Report.Total, tt.wantDamage)
+			}
+			if result.HPBefore != 30 || result.HPAfter != tt.wantHPAfter {
+				t.Errorf("HP %d -> %d, want 30 -> %d", result.HPBefore, result.HPAfter, tt.wantHPAfter)
+			}
+			wantHP := tt.wantHPAfter
+			if tt.dryRun {
+				wantHP = 30
+			}
+			if goblin.HP() != wantHP || result.Applied == tt.dryRun {
+				t.Errorf("goblin HP = %d (applied %v), want %d", goblin.HP(), result.Applied, wantHP)
+			}
+			if !tt.wantHit && len(result.Damage) != 0 {
+				t.Errorf("a miss should roll no damage, rolled %d", len(result.Damage))
+			}
+			if remaining := roller.source.(*Script).Remaining(); remaining != 0 {
+				t.Errorf("%d scripted values left unrolled", remaining)
+			}
+		})
+	}
+}
+
+func TestActor_AttackDetail(t *testing.T) {
+	fighter, _ := NewActor("fighter").WithHP(40).WithCombatModifier("strength", 3).Build()
+	goblin, _ := NewActor("goblin").WithHP(7).WithAC(15).Build()
+	roller := NewScriptedRoller(NewScript(12, 6))
+	profile := AttackProfile{Name: "longsword", Damage: []DamageRoll{{Notation: "1d8+3", Type: Slashing}}}
+
+	result, err := fighter.Attack(goblin, profile, roller).WithModifier("bless", 1).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	want := strings.Join([]string{
+		"fighter attacks goblin with longsword: *Hit!*",
+		"Rolled 1d20... 12; +3 strength, +1 bless; *Result: 16*",
+		"Rolled 1d8... 6; +3 modifier; *Result: 9*",
+		"Took 9 damage... 9 slashing; *HP: 0*",
+	}, "\n")
+	if result.Detail != want {
+		t.Errorf("Detail = %q, want %q", result.Detail, want)
+	}
+	if !goblin.IsKnockedOut() {
+		t.Error("goblin should be knocked out")
+	}
+}
+
+func TestActor_AttackErrors(t *testing.T) {
+	fighter, _ := NewActor("fighter").WithHP(40).Build()
+	goblin, _ := NewActor("goblin").WithHP(7).Build()
+	roller := NewScriptedRoller(NewScript(20))
+
+	if _, err := fighter.Attack(nil, AttackProfile{}, roller).Resolve(); err == nil {
+		t.Error("Resolve() should require a target")
+	}
+	profile := AttackProfile{Name: "broken", Damage: []DamageRoll{{Notation: "1d"}}}
+	if _, err := fighter.Attack(goblin, profile, roller).Resolve(); err == nil {
+		t.Error("Resolve() should reject invalid damage notation")
+	}
+	flat := AttackProfile{Name: "flat", Damage: []DamageRoll{{Notation: "5"}}}
+	if _, err := fighter.Attack(goblin, flat, roller).Resolve(); err == nil {
+		t.Error("Resolve() should reject damage notation without dice")
+	}
+	if roller.source.(*Script).Remaining() != 1 {
+		t.Error("an invalid profile should not roll the attack")
+	}
+
+	upper := AttackProfile{Name: "club", Damage: []DamageRoll{{Notation: "1D4", Type: Bludgeoning}}}
+	result, err := fighter.Attack(goblin, upper, NewScriptedRoller(NewScript(15, 3))).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if !result.Hit || result.Report.Total != 3 {
+		t.Errorf("Hit = %v, damage = %d; want a hit for 3", result.Hit, result.Report.Total)
+	}
+}
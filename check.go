@@ -0,0 +1,84 @@
+package d20
+
+// SuccessRule decides whether a roll succeeds against a difficulty class.
+// Create rules with SkillCheckRule, AttackRollRule or RollUnderRule, or set
+// the fields directly for other systems.
+type SuccessRule struct {
+	RollUnder        bool // Succeed when the total is at most the DC instead of at least
+	CriticalSucceeds bool // A critical success always succeeds, whatever the total
+	FumbleFails      bool // A fumble always fails, whatever the total
+}
+
+// SkillCheckRule succeeds when the total meets or beats the DC. Natural
+// results have no special effect, as for D&D 5e ability checks and saving throws.
+func SkillCheckRule() SuccessRule {
+	return SuccessRule{}
+}
+
+// AttackRollRule succeeds when the total meets or beats the DC, except that a
+// critical always hits and a fumble always misses, as for D&D 5e attack rolls.
+func AttackRollRule() SuccessRule {
+	return SuccessRule{CriticalSucceeds: true, FumbleFails: true}
+}
+
+// RollUnderRule succeeds when the total is at most the DC, as for percentile
+// and roll-under systems.
+func RollUnderRule() SuccessRule {
+	return SuccessRule{RollUnder: true}
+}
+
+// margin returns how far the total beat the DC; negative when it fell short.
+func (r SuccessRule) margin(value, dc int) int {
+	if r.RollUnder {
+		return dc - value
+	}
+	return value - dc
+}
+
+// CheckResult is the result of a roll against a difficulty class.
+// The embedded RollOutcome carries the total and the natural result.
+type CheckResult struct {
+	RollOutcome
+	DC        int  // Difficulty class the roll was made against
+	Success   bool // Whether the roll succeeded under the success rule
+	Margin    int  // Amount the total beat the DC by; negative when it fell short
+	Automatic bool // Success was decided by a critical or fumble rather than the total
+}
+
+// WithSuccessRule sets how Against decides success. Rolls use
+// SkillCheckRule unless configured otherwise; AttackRoll uses AttackRollRule.
+//
+// Example:
+//
+//	check, _ := roller.Dice(1, 100).WithSuccessRule(d20.RollUnderRule()).Against(45)
+func (rb *RollBuilder) WithSuccessRule(rule SuccessRule) *RollBuilder {
+	rb.successRule = rule
+	return rb
+}
+
+// Against executes the roll and checks it against a difficulty class,
+// such as a DC for a skill check or an AC for an attack roll.
+//
+// Example:
+//
+//	check, _ := actor.AttackRoll(roller).Against(target.AC())
+//	if check.Success {
+//		fmt.Printf("Hit by %d\n", check.Margin)
+//	}
+func (rb *RollBuilder) Against(dc int) (CheckResult, error) {
+	outcome, err := rb.Roll()
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	rule := rb.successRule
+	margin := rule.margin(outcome.Value, dc)
+	result := CheckResult{RollOutcome: outcome, DC: dc, Success: margin >= 0, Margin: margin}
+	switch {
+	case outcome.IsCritical && rule.CriticalSucceeds:
+		result.Success, result.Automatic = true, true
+	case outcome.IsFumble && rule.FumbleFails:
+		result.Success, result.Automatic = false, true
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package d20
+
+import "testing"
+
+func TestRollBuilder_Against(t *testing.T) {
+	tests := []struct {
+		name          string
+		roll          int
+		modifier      int
+		dc            int
+		rule          SuccessRule
+		wantSuccess   bool
+		wantMargin    int
+		wantAutomatic bool
+	}{
+		{name: "Meets the DC", roll: 12, modifier: 3, dc: 15, rule: SkillCheckRule(), wantSuccess: true, wantMargin: 0},
+		{name: "Beats the DC", roll: 18, modifier: 3, dc: 15, rule: SkillCheckRule(), wantSuccess: true, wantMargin: 6},
+		{name: "Falls short", roll: 5, modifier: 3, dc: 15, rule: SkillCheckRule(), wantSuccess: false, wantMargin: -7},
+		{name: "Skill natural 20 can fail", roll: 20, modifier: 0, dc: 25, rule: SkillCheckRule(), wantSuccess: false, wantMargin: -5},
+		{name: "Skill natural 1 can succeed", roll: 1, modifier: 10, dc: 10, rule: SkillCheckRule(), wantSuccess: true, wantMargin: 1},
+		{name: "Attack natural 20 always hits", roll: 20, modifier: 0, dc: 25, rule: AttackRollRule(), wantSuccess: true, wantMargin: -5, wantAutomatic: true},
+		{name: "Attack natural 1 always misses", roll: 1, modifier: 10, dc: 10, rule: AttackRollRule(), wantSuccess: false, wantMargin: 1, wantAutomatic: true},
+		{name: "Attack otherwise uses the total", roll: 14, modifier: 5, dc: 19, rule: AttackRollRule(), wantSuccess: true, wantMargin: 0},
+		{name: "Roll under succeeds at the DC", roll: 15, modifier: 0, dc: 15, rule: RollUnderRule(), wantSuccess: true, wantMargin: 0},
+		{name: "Roll under fails above the DC", roll: 17, modifier: 0, dc: 15, rule: RollUnderRule(), wantSuccess: false, wantMargin: -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roller := NewScriptedRoller(NewScript(tt.roll))
+			check, err := roller.Dice(1, 20).
+				WithModifier("bonus", tt.modifier).
+				WithSuccessRule(tt.rule).
+				Against(tt.dc)
+			if err != nil {
+				t.Fatalf("Against() error: %v", err)
+			}
+			if check.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", check.Success, tt.wantSuccess)
+			}
+			if check.Margin != tt.wantMargin {
+				t.Errorf("Margin = %d, want %d", check.Margin, tt.wantMargin)
+			}
+			if check.Automatic != tt.wantAutomatic {
+				t.Errorf("Automatic = %v, want %v", check.Automatic, tt.wantAutomatic)
+			}
+			if check.DC != tt.dc || check.Natural != tt.roll {
+				t.Errorf("DC = %d, Natural = %d; want %d, %d", check.DC, check.Natural, tt.dc, tt.roll)
+			}
+		})
+	}
+}
+
+func TestRollBuilder_AgainstError(t *testing.T) {
+	roller := NewScriptedRoller(NewScript())
+	if _, err := roller.Dice(1, 20).Against(10); err == nil {
+		t.Error("Against() should return the roll's error")
+	}
+}
+
+func TestActor_AttackRollAgainst(t *testing.T) {
+	attacker, err := NewActor("fighter").WithHP(10).WithCombatModifier("strength", 3).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	attacker.SetAttribute("athletics", 0)
+
+	roller := NewScriptedRoller(NewScript(20, 20))
+	attack, err := attacker.AttackRoll(roller).Against(30)
+	if err != nil {
+		t.Fatalf("Against() error: %v", err)
+	}
+	if !attack.Success {
+		t.Error("a natural 20 attack should hit any AC")
+	}
+
+	builder, err := attacker.SkillCheck("athletics", roller)
+	if err != nil {
+		t.Fatalf("SkillCheck() error: %v", err)
+	}
+	check, err := builder.Against(30)
+	if err != nil {
+		t.Fatalf("Against() error: %v", err)
+	}
+	if check.Success {
+		t.Error("a natural 20 skill check should not beat DC 30")
+	}
+}
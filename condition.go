@@ -0,0 +1,80 @@
+package d20
+
+import "fmt"
+
+// compareOp is the comparison a Condition applies to a die value.
+type compareOp int
+
+const (
+	compareNone    compareOp = iota // Zero value; matches nothing
+	compareEqual                    // "="
+	compareAtLeast                  // ">="
+	compareAtMost                   // "<="
+)
+
+// compareNotation maps notation operators to comparisons. As in Roll20,
+// the bare ">" and "<" operators are inclusive, so "2d6ro<2" rerolls 1s and 2s.
+var compareNotation = map[string]compareOp{
+	"=":  compareEqual,
+	">=": compareAtLeast,
+	"<=": compareAtMost,
+	">":  compareAtLeast,
+	"<":  compareAtMost,
+}
+
+// compareSymbols is the canonical notation for each comparison.
+var compareSymbols = map[compareOp]string{
+	compareEqual:   "=",
+	compareAtLeast: ">=",
+	compareAtMost:  "<=",
+}
+
+// Condition is a test applied to a single die value, used to decide which
+// dice explode, are rerolled, or count as successes.
+// Create conditions with Equals, AtLeast, or AtMost.
+// The zero Condition matches nothing.
+type Condition struct {
+	op    compareOp
+	value int
+}
+
+// Equals matches dice showing exactly n.
+func Equals(n int) Condition {
+	return Condition{op: compareEqual, value: n}
+}
+
+// AtLeast matches dice showing n or higher.
+func AtLeast(n int) Condition {
+	return Condition{op: compareAtLeast, value: n}
+}
+
+// AtMost matches dice showing n or lower.
+func AtMost(n int) Condition {
+	return Condition{op: compareAtMost, value: n}
+}
+
+// Matches reports whether the die value satisfies the condition.
+func (c Condition) Matches(value int) bool {
+	switch c.op {
+	case compareEqual:
+		return value == c.value
+	case compareAtLeast:
+		return value >= c.value
+	case compareAtMost:
+		return value <= c.value
+	}
+	return false
+}
+
+// IsZero reports whether the condition is unset.
+func (c Condition) IsZero() bool {
+	return c.op == compareNone
+}
+
+// String formats the condition in dice notation, e.g. ">=5".
+func (c Condition) String() string {
+	if c.op == compareNone {
+		return ""
+	}
+	return fmt.Sprintf("%s%d", compareSymbols[c.op], c.value)
+}
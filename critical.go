@@ -0,0 +1,103 @@
+package d20
+
+// critRule sets which natural results of a dice term are critical successes
+// and fumbles. Crits are only determined for terms that keep a single die.
+type critRule struct {
+	success Condition // Natural results that crit; the zero Condition means the maximum face
+	failure Condition // Natural results that fumble; the zero Condition means 1
+}
+
+// applies reports whether crits are checked for the term. A d20 checks crits
+// by default; other dice only do when a crit or fumble range is configured.
+func (c critRule) applies(n *diceNode) bool {
+	if !c.success.IsZero() || !c.failure.IsZero() {
+		return true
+	}
+	return n.kind == StandardDie && n.faces == 20
+}
+
+// successOn returns the condition for a critical success on the term.
+func (c critRule) successOn(n *diceNode) Condition {
+	if c.success.IsZero() {
+		return Equals(n.maxFace())
+	}
+	return c.success
+}
+
+// failureOn returns the condition for a fumble on the term.
+func (c critRule) failureOn() Condition {
+	if c.failure.IsZero() {
+		return Equals(1)
+	}
+	return c.failure
+}
+
+// String formats the rule as a notation suffix, e.g. "cs>=19cf<=2".
+func (c critRule) String() string {
+	s := ""
+	if !c.success.IsZero() {
+		s += "cs" + c.success.String()
+	}
+	if !c.failure.IsZero() {
+		s += "cf" + c.failure.String()
+	}
+	return s
+}
+
+// natural returns the natural result of the primary term: the value of its
+// only kept die. Returns false if the term kept more or fewer than one die.
+func (ev *evaluation) natural() (int, bool) {
+	natural, kept := 0, 0
+	for _, die := range ev.results[ev.primary] {
+		if !die.Dropped {
+			natural = die.Value
+			kept++
+		}
+	}
+	return natural, kept == 1
+}
+
+// crits returns the natural result of the roll and whether it is a critical
+// success or fumble. Success-counting pools have no natural result.
+func (ev *evaluation) crits() (natural int, critical, fumble bool) {
+	natural, ok := ev.natural()
+	if !ok || ev.primary.pool.counts() {
+		return 0, false, false
+	}
+
+	rule := ev.primary.crit
+	if !rule.applies(ev.primary) {
+		return natural, false, false
+	}
+	critical = rule.successOn(ev.primary).Matches(natural)
+	fumble = !critical && rule.failureOn().Matches(natural)
+	return natural, critical, fumble
+}
+
+// CritRange makes natural results of min or higher critical successes,
+// e.g. CritRange(19) for a Champion fighter's Improved Critical.
+// Equivalent to the "cs" notation suffix, e.g. "1d20cs>=19".
+//
+// Example:
+//
+//	result, _ := roller.Dice(1, 20).CritRange(19).Roll()
+//	if result.IsCritical {
+//		fmt.Println("Critical hit!")
+//	}
+func (rb *RollBuilder) CritRange(min int) *RollBuilder {
+	return rb.CritOn(AtLeast(min))
+}
+
+// CritOn sets which natural results are critical successes, replacing the
+// default of the maximum face. Enables crit checks on dice other than a d20.
+func (rb *RollBuilder) CritOn(on Condition) *RollBuilder {
+	rb.primary.crit.success = on
+	return rb
+}
+
+// FumbleOn sets which natural results are fumbles, replacing the default of 1.
+// Equivalent to the "cf" notation suffix, e.g. "1d20cf<=2".
+func (rb *RollBuilder) FumbleOn(on Condition) *RollBuilder {
+	rb.primary.crit.failure = on
+	return rb
+}
@@ -0,0 +1,124 @@
+package d20
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRollBuilder_Crits(t *testing.T) {
+	roller := NewRoller(42)
+
+	tests := []struct {
+		name         string
+		builder      func() *RollBuilder
+		wantCritical func(natural int) bool
+		wantFumble   func(natural int) bool
+	}{
+		{
+			name:         "Natural 20 and 1 on a d20",
+			builder:      func() *RollBuilder { return roller.Dice(1, 20).WithModifier("strength", 3) },
+			wantCritical: func(n int) bool { return n == 20 },
+			wantFumble:   func(n int) bool { return n == 1 },
+		},
+		{
+			name:         "Advantage uses the kept die",
+			builder:      func() *RollBuilder { return roller.Dice(1, 20).WithAdvantage() },
+			wantCritical: func(n int) bool { return n == 20 },
+			wantFumble:   func(n int) bool { return n == 1 },
+		},
+		{
+			name:         "Improved Critical range",
+			builder:      func() *RollBuilder { return roller.Dice(1, 20).CritRange(18) },
+			wantCritical: func(n int) bool { return n >= 18 },
+			wantFumble:   func(n int) bool { return n == 1 },
+		},
+		{
+			name:         "Custom fumble range",
+			builder:      func() *RollBuilder { return roller.Dice(1, 20).FumbleOn(AtMost(2)) },
+			wantCritical: func(n int) bool { return n == 20 },
+			wantFumble:   func(n int) bool { return n <= 2 },
+		},
+		{
+			name:         "Other dice do not crit by default",
+			builder:      func() *RollBuilder { return roller.Dice(1, 6) },
+			wantCritical: func(int) bool { return false },
+			wantFumble:   func(int) bool { return false },
+		},
+		{
+			name:         "Other dice crit when configured",
+			builder:      func() *RollBuilder { return roller.Dice(1, 6).CritOn(Equals(6)) },
+			wantCritical: func(n int) bool { return n == 6 },
+			wantFumble:   func(n int) bool { return n == 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 200 {
+				result, err := tt.builder().Roll()
+				if err != nil {
+					t.Fatalf("Roll() error: %v", err)
+				}
+				kept := result.Kept()
+				if len(kept) != 1 || result.Natural != kept[0] {
+					t.Fatalf("Natural = %d, want kept die %v", result.Natural, kept)
+				}
+				if want := tt.wantCritical(result.Natural); result.IsCritical != want {
+					t.Errorf("natural %d: IsCritical = %v, want %v", result.Natural, result.IsCritical, want)
+				}
+				if want := tt.wantFumble(result.Natural); result.IsFumble != want {
+					t.Errorf("natural %d: IsFumble = %v, want %v", result.Natural, result.IsFumble, want)
+				}
+				if strings.Contains(result.Detail, "*Critical!*") != result.IsCritical {
+					t.Errorf("detail should call out crits: %q", result.Detail)
+				}
+				if strings.Contains(result.Detail, "*Fumble!*") != result.IsFumble {
+					t.Errorf("detail should call out fumbles: %q", result.Detail)
+				}
+			}
+		})
+	}
+}
+
+func TestRollBuilder_CritsNeedSingleKeptDie(t *testing.T) {
+	roller := NewRoller(42)
+
+	result, err := roller.Roll("2d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if result.Natural != 0 || result.IsCritical || result.IsFumble {
+		t.Errorf("2d20 should have no natural result, got %+v", result)
+	}
+
+	result, err = roller.Roll("3d20kh1+1d4")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if result.Natural != max(result.DiceRolls[0], result.DiceRolls[1], result.DiceRolls[2]) {
+		t.Errorf("Natural = %d, want highest of %v", result.Natural, result.DiceRolls[:3])
+	}
+
+	pool, err := roller.RollPool("1d20>=10")
+	if err != nil {
+		t.Fatalf("RollPool() error: %v", err)
+	}
+	if pool.Natural != 0 || pool.IsCritical || pool.IsFumble {
+		t.Errorf("pools should have no natural result, got %+v", pool.RollOutcome)
+	}
+}
+
+func TestRoller_CritNotation(t *testing.T) {
+	roller := NewRoller(42)
+
+	result, err := roller.Roll("1d1cs1")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if !result.IsCritical || result.IsFumble {
+		t.Errorf("1d1cs1 should always crit, got %+v", result)
+	}
+	if !strings.HasSuffix(result.Detail, "; *Critical!*") {
+		t.Errorf("unexpected detail: %q", result.Detail)
+	}
+}
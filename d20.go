@@ -1,4 +1,4 @@
 // Package d20 provides dice rolling functionality for tabletop gaming.
-// It supports standard dice notation (e.g., "2d6", "1d20+5") and provides
+// It supports dice notation expressions (e.g., "2d6", "1d20+5", "2d6+1d4+3") and provides
 // a seedable random number generator for reproducible results.
 package d20
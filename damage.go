@@ -0,0 +1,245 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var errInvalidMultiplier = errors.New("damage multiplier denominator must be positive")
+
+// CritDamage is how a damage roll is scaled for a critical hit.
+type CritDamage int
+
+const (
+	NormalDamage CritDamage = iota // Not a critical hit
+	DoubleDice                     // Roll twice as many dice, adding modifiers once (D&D 5e)
+	MaxPlusRoll                    // Add the dice's maximum to a normal roll
+	DoubleTotal                    // Double the total, including modifiers (Pathfinder 2e)
+)
+
+// String returns the name of the crit mode, e.g. "double dice".
+func (c CritDamage) String() string {
+	switch c {
+	case DoubleDice:
+		return "double dice"
+	case MaxPlusRoll:
+		return "max plus roll"
+	case DoubleTotal:
+		return "double total"
+	}
+	return "normal"
+}
+
+// DamageMultiplier scales a damage total after modifiers, such as halving
+// for resistance. The result is rounded down.
+type DamageMultiplier struct {
+	Reason      string // Why the damage is scaled, e.g. "resistance"
+	Numerator   int
+	Denominator int
+}
+
+// validate checks that the multiplier can be applied.
+func (m DamageMultiplier) validate() error {
+	if m.Denominator <= 0 {
+		return fmt.Errorf("%w: %s %d/%d", errInvalidMultiplier, m.Reason, m.Numerator, m.Denominator)
+	}
+	return nil
+}
+
+// apply scales the value, rounding down.
+func (m DamageMultiplier) apply(value int) int {
+	return floorDiv(value*m.Numerator, m.Denominator)
+}
+
+// String formats the multiplier as a factor, e.g. "x2" or "x1/2".
+func (m DamageMultiplier) String() string {
+	if m.Denominator == 1 {
+		return fmt.Sprintf("x%d", m.Numerator)
+	}
+	return fmt.Sprintf("x%d/%d", m.Numerator, m.Denominator)
+}
+
+// CriticalDamage rolls the damage as a critical hit, scaled by the given mode.
+// Flat modifiers are added once unless the mode doubles the total. Dice
+// modifiers, such as sneak attack's extra dice, scale with the roll's own
+// dice: DoubleDice doubles them and MaxPlusRoll adds their maximum.
+//
+// Example:
+//
+//	attack, _ := actor.AttackRoll(roller).Against(target.AC())
+//	damage := roller.Dice(1, 8).WithModifier("strength", 3)
+//	if attack.IsCritical {
+//		damage = damage.CriticalDamage(d20.DoubleDice) // Rolls 2d8+3
+//	}
+func (rb *RollBuilder) CriticalDamage(mode CritDamage) *RollBuilder {
+	rb.critDamage = mode
+	return rb
+}
+
+// WithMultiplier scales the total after modifiers and any critical by
+// numerator/denominator, rounding down. Multipliers apply in the order added,
+// each rounding down before the next, as D&D 5e applies resistance before
+// vulnerability.
+//
+// Example:
+//
+//	roller.Dice(8, 6).WithMultiplier("evasion", 1, 2).Roll()
+func (rb *RollBuilder) WithMultiplier(reason string, numerator, denominator int) *RollBuilder {
+	rb.multipliers = append(rb.multipliers, DamageMultiplier{Reason: reason, Numerator: numerator, Denominator: denominator})
+	return rb
+}
+
+// WithResistance halves the total, rounding down.
+//
+// Example:
+//
+//	roller.Dice(8, 6).WithResistance("fire resistance").Roll()
+func (rb *RollBuilder) WithResistance(reason string) *RollBuilder {
+	return rb.WithMultiplier(reason, 1, 2)
+}
+
+// WithVulnerability doubles the total.
+func (rb *RollBuilder) WithVulnerability(reason string) *RollBuilder {
+	return rb.WithMultiplier(reason, 2, 1)
+}
+
+// validateDamage checks the roll's damage multipliers.
+func (rb *RollBuilder) validateDamage() error {
+	for _, m := range rb.multipliers {
+		if err := m.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// damageExpr returns the expression to roll and its primary dice term,
+// with every dice term doubled for a DoubleDice critical.
+func (rb *RollBuilder) damageExpr() (exprNode, *diceNode) {
+	if rb.critDamage != DoubleDice {
+		return rb.expr, rb.primary
+	}
+	expr := doubleDice(rb.expr)
+	return expr, firstDice(expr)
+}
+
+// damageModifiers returns the roll's modifiers with the dice of every dice
+// modifier doubled for a DoubleDice critical. Invalid notation is left for
+// the roll to report.
+func (rb *RollBuilder) damageModifiers() []Modifier {
+	if rb.critDamage != DoubleDice {
+		return rb.modifiers
+	}
+	modifiers := slices.Clone(rb.modifiers)
+	for i, mod := range modifiers {
+		if !mod.IsDice() {
+			continue
+		}
+		if expr, err := parseNotation(mod.Dice); err == nil {
+			modifiers[i].Dice = doubleDice(expr).String()
+		}
+	}
+	return modifiers
+}
+
+// scaleDamage applies the critical mode and multipliers to a total, returning
+// the scaled total and a description of each step for the detail. modifiers
+// are the modifiers that applied, with dice modifiers already scaled for
+// DoubleDice.
+func (rb *RollBuilder) scaleDamage(expr exprNode, modifiers []Modifier, total int) (int, []string) {
+	var steps []string
+	switch rb.critDamage {
+	case DoubleDice:
+		step := fmt.Sprintf("critical: %s doubled to %s", rb.expr, expr)
+		for _, mod := range modifiers {
+			if mod.IsDice() {
+				step += fmt.Sprintf(", %s doubled to %s", mod.Reason, mod.Dice)
+			}
+		}
+		steps = append(steps, step)
+	case MaxPlusRoll:
+		bonus := maxDice(expr) + maxDiceModifiers(modifiers)
+		total += bonus
+		steps = append(steps, fmt.Sprintf("critical max dice %+d = %d", bonus, total))
+	case DoubleTotal:
+		total *= 2
+		steps = append(steps, fmt.Sprintf("critical x2 = %d", total))
+	}
+	for _, m := range rb.multipliers {
+		total = m.apply(total)
+		steps = append(steps, fmt.Sprintf("%s %s = %d", m.Reason, m, total))
+	}
+	return total, steps
+}
+
+// maxDiceModifiers returns the total of the dice modifiers with every die
+// showing its maximum face.
+func maxDiceModifiers(modifiers []Modifier) int {
+	total := 0
+	for _, mod := range modifiers {
+		if !mod.IsDice() {
+			continue
+		}
+		if expr, err := parseNotation(mod.Dice); err == nil {
+			total += maxDice(expr)
+		}
+	}
+	return total
+}
+
+// doubleDice returns a copy of the expression with every dice term rolling
+// twice as many dice.
+func doubleDice(node exprNode) exprNode {
+	switch n := node.(type) {
+	case *diceNode:
+		doubled := *n
+		doubled.count *= 2
+		doubled.keep.n *= 2
+		return &doubled
+	case *binaryNode:
+		return &binaryNode{op: n.op, left: doubleDice(n.left), right: doubleDice(n.right)}
+	case *negateNode:
+		return &negateNode{operand: doubleDice(n.operand)}
+	case *groupNode:
+		return &groupNode{inner: doubleDice(n.inner)}
+	}
+	return node
+}
+
+// maxDice evaluates the expression with every kept die showing its maximum
+// face, ignoring explosions.
+func maxDice(node exprNode) int {
+	switch n := node.(type) {
+	case *numberNode:
+		return n.value
+	case *diceNode:
+		kept := n.count
+		switch n.keep.mode {
+		case keepHighest, keepLowest:
+			kept = n.keep.n
+		case dropHighest, dropLowest:
+			kept -= n.keep.n
+		}
+		return int(kept) * n.maxFace()
+	case *binaryNode:
+		left, right := maxDice(n.left), maxDice(n.right)
+		switch n.op {
+		case '+':
+			return left + right
+		case '-':
+			return left - right
+		case '*':
+			return left * right
+		case '/':
+			if right != 0 {
+				return floorDiv(left, right)
+			}
+		}
+	case *negateNode:
+		return -maxDice(n.operand)
+	case *groupNode:
+		return maxDice(n.inner)
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package d20
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRollBuilder_DamageScaling(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     []int
+		builder    func(*Roller) *RollBuilder
+		wantValue  int
+		wantDetail string
+	}{
+		{
+			name:   "Double dice",
+			script: []int{3, 5},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 8).WithModifier("strength", 3).CriticalDamage(DoubleDice)
+			},
+			wantValue:  11,
+			wantDetail: "Rolled 2d8... 3, 5; +3 strength; critical: 1d8 doubled to 2d8; *Result: 11*",
+		},
+		{
+			name:   "Double dice in every term",
+			script: []int{3, 5, 1, 2},
+			builder: func(r *Roller) *RollBuilder {
+				b, _ := r.Notation("1d8+1d4+3")
+				return b.CriticalDamage(DoubleDice)
+			},
+			wantValue:  14,
+			wantDetail: "Rolled 2d8+2d4... [3, 5] + [1, 2]; +3 modifier; critical: 1d8+1d4 doubled to 2d8+2d4; *Result: 14*",
+		},
+		{
+			name:   "Max plus roll",
+			script: []int{3, 5},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(2, 6).WithModifier("strength", 3).CriticalDamage(MaxPlusRoll)
+			},
+			wantValue:  23,
+			wantDetail: "Rolled 2d6... 3, 5; +3 strength; critical max dice +12 = 23; *Result: 23*",
+		},
+		{
+			name:   "Double dice modifiers",
+			script: []int{3, 5, 2, 4},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 8).WithModifier("dexterity", 3).WithDiceModifier("sneak attack", "1d6").CriticalDamage(DoubleDice)
+			},
+			wantValue:  17,
+			wantDetail: "Rolled 2d8... 3, 5; +3 dexterity, +2d6 (6) sneak attack; critical: 1d8 doubled to 2d8, sneak attack doubled to 2d6; *Result: 17*",
+		},
+		{
+			name:   "Max plus roll dice modifiers",
+			script: []int{3, 2},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 8).WithModifier("dexterity", 3).WithDiceModifier("sneak attack", "1d6").CriticalDamage(MaxPlusRoll)
+			},
+			wantValue:  22,
+			wantDetail: "Rolled 1d8... 3; +3 dexterity, +1d6 (2) sneak attack; critical max dice +14 = 22; *Result: 22*",
+		},
+		{
+			name:   "Double total",
+			script: []int{3, 5},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(2, 6).WithModifier("strength", 3).CriticalDamage(DoubleTotal)
+			},
+			wantValue:  22,
+			wantDetail: "Rolled 2d6... 3, 5; +3 strength; critical x2 = 22; *Result: 22*",
+		},
+		{
+			name:   "Resistance rounds down",
+			script: []int{3, 5},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(2, 6).WithModifier("strength", 3).WithResistance("fire resistance")
+			},
+			wantValue:  5,
+			wantDetail: "Rolled 2d6... 3, 5; +3 strength; fire resistance x1/2 = 5; *Result: 5*",
+		},
+		{
+			name:   "Steps apply in order",
+			script: []int{3, 5, 4, 1},
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(2, 6).WithModifier("strength", 3).
+					CriticalDamage(DoubleDice).
+					WithResistance("resistance").
+					WithVulnerability("vulnerability")
+			},
+			wantValue:  16,
+			wantDetail: "Rolled 4d6... 3, 5, 4, 1; +3 strength; critical: 2d6 doubled to 4d6; resistance x1/2 = 8; vulnerability x2 = 16; *Result: 16*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roller := NewScriptedRoller(NewScript(tt.script...))
+			result, err := tt.builder(roller).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			if result.Value != tt.wantValue {
+				t.Errorf("Value = %d, want %d", result.Value, tt.wantValue)
+			}
+			if result.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", result.Detail, tt.wantDetail)
+			}
+			if remaining := roller.source.(*Script).Remaining(); remaining != 0 {
+				t.Errorf("%d scripted values left unrolled", remaining)
+			}
+		})
+	}
+}
+
+func TestRollBuilder_InvalidMultiplier(t *testing.T) {
+	_, err := NewRoller(42).Dice(1, 6).WithMultiplier("broken", 1, 0).Roll()
+	if !errors.Is(err, errInvalidMultiplier) {
+		t.Errorf("Roll() error = %v, want %v", err, errInvalidMultiplier)
+	}
+}
+
+func TestRollBuilder_DamageDistribution(t *testing.T) {
+	roller := NewRoller(42)
+	tests := []struct {
+		name     string
+		builder  *RollBuilder
+		wantMin  int
+		wantMax  int
+		wantMean float64
+	}{
+		{name: "Double dice", builder: roller.Dice(1, 8).WithModifier("strength", 3).CriticalDamage(DoubleDice), wantMin: 5, wantMax: 19, wantMean: 12},
+		{name: "Max plus roll", builder: roller.Dice(1, 8).WithModifier("strength", 3).CriticalDamage(MaxPlusRoll), wantMin: 12, wantMax: 19, wantMean: 15.5},
+		{name: "Double total", builder: roller.Dice(1, 8).WithModifier("strength", 3).CriticalDamage(DoubleTotal), wantMin: 8, wantMax: 22, wantMean: 15},
+		{name: "Resistance", builder: roller.Dice(1, 6).WithResistance("resistance"), wantMin: 0, wantMax: 3, wantMean: 1.5},
+		{name: "Double dice modifiers", builder: roller.Dice(1, 8).WithDiceModifier("sneak attack", "1d6").CriticalDamage(DoubleDice), wantMin: 4, wantMax: 28, wantMean: 16},
+		{name: "Max plus roll dice modifiers", builder: roller.Dice(1, 8).WithDiceModifier("sneak attack", "1d6").CriticalDamage(MaxPlusRoll), wantMin: 16, wantMax: 28, wantMean: 22},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, err := tt.builder.Distribution()
+			if err != nil {
+				t.Fatalf("Distribution() error: %v", err)
+			}
+			if dist.Min() != tt.wantMin || dist.Max() != tt.wantMax {
+				t.Errorf("range = %d-%d, want %d-%d", dist.Min(), dist.Max(), tt.wantMin, tt.wantMax)
+			}
+			if got := dist.Mean(); math.Abs(got-tt.wantMean) > probabilityTolerance {
+				t.Errorf("Mean() = %v, want %v", got, tt.wantMean)
+			}
+		})
+	}
+}
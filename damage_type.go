@@ -0,0 +1,239 @@
+package d20
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DamageType is the kind of damage dealt, such as fire or slashing.
+// Any lowercase name can be used as a type, e.g. DamageType("holy").
+type DamageType string
+
+// D&D 5e damage types.
+const (
+	Acid        DamageType = "acid"
+	Bludgeoning DamageType = "bludgeoning"
+	Cold        DamageType = "cold"
+	Fire        DamageType = "fire"
+	Force       DamageType = "force"
+	Lightning   DamageType = "lightning"
+	Necrotic    DamageType = "necrotic"
+	Piercing    DamageType = "piercing"
+	Poison      DamageType = "poison"
+	Psychic     DamageType = "psychic"
+	Radiant     DamageType = "radiant"
+	Slashing    DamageType = "slashing"
+	Thunder     DamageType = "thunder"
+)
+
+// normalize lowercases the damage type for consistent lookups.
+func (t DamageType) normalize() DamageType {
+	return DamageType(strings.ToLower(string(t)))
+}
+
+// damageTraits records how an actor responds to a damage type.
+type damageTraits uint8
+
+const (
+	resistant  damageTraits = 1 << iota // Takes half damage
+	vulnerable                          // Takes double damage
+	immune                              // Takes no damage
+)
+
+// Damage is one typed component of a hit, such as the 2d6 fire of a
+// flame tongue longsword alongside its 1d8 slashing.
+type Damage struct {
+	Amount int        // Damage rolled, after modifiers and any critical
+	Type   DamageType // Untyped damage ignores resistances and immunities
+}
+
+// DamageDealt is the result of applying one type of damage to an actor.
+type DamageDealt struct {
+	Damage          // Total rolled for the type
+	Dealt      int  // Damage actually dealt after resistance, vulnerability and immunity
+	Resisted   bool // The actor resists the type, halving the damage
+	Vulnerable bool // The actor is vulnerable to the type, doubling the damage
+	Immune     bool // The actor is immune to the type, ignoring the damage
+}
+
+// String formats the component for a report, e.g. "3 fire (resisted, 7 rolled)".
+func (d DamageDealt) String() string {
+	s := fmt.Sprintf("%d", d.Dealt)
+	if d.Type != "" {
+		s += " " + string(d.Type)
+	}
+
+	var traits []string
+	switch {
+	case d.Immune:
+		traits = append(traits, "immune")
+	default:
+		if d.Resisted {
+			traits = append(traits, "resisted")
+		}
+		if d.Vulnerable {
+			traits = append(traits, "vulnerable")
+		}
+	}
+	if d.Dealt != d.Amount {
+		traits = append(traits, fmt.Sprintf("%d rolled", d.Amount))
+	}
+	if len(traits) > 0 {
+		s += " (" + strings.Join(traits, ", ") + ")"
+	}
+	return s
+}
+
+// DamageReport describes the damage an actor took from one hit.
+type DamageReport struct {
+	Components []DamageDealt // Damage of each type, in the order the types first appear
+	Total      int           // Total damage dealt across all components
+	Absorbed   int           // Part of the total absorbed by temporary HP
+	HP         int           // Actor's HP after the damage
+	TempHP     int           // Actor's temporary HP after the damage
+	Detail     string        // Formatted description, e.g. "Took 9 damage... 6 slashing, 3 fire (resisted, 7 rolled); *HP: 12*"
+}
+
+// dealt applies the actor's traits for the damage type to an amount,
+// following D&D 5e: immunity negates the damage, and resistance halves it
+// (rounding down) before vulnerability doubles it. Negative amounts deal 0.
+func (a *Actor) dealt(damage Damage) DamageDealt {
+	traits := a.damageTraits[damage.Type.normalize()]
+	result := DamageDealt{
+		Damage:     damage,
+		Dealt:      max(damage.Amount, 0),
+		Resisted:   traits&resistant != 0,
+		Vulnerable: traits&vulnerable != 0,
+		Immune:     traits&immune != 0,
+	}
+	switch {
+	case result.Immune:
+		result.Dealt = 0
+		result.Resisted, result.Vulnerable = false, false
+	default:
+		if result.Resisted {
+			result.Dealt /= 2
+		}
+		if result.Vulnerable {
+			result.Dealt *= 2
+		}
+	}
+	return result
+}
+
+// ApplyDamage deals one hit's typed damage components to the actor and
+// reports what was actually dealt. Components of the same type are added
+// together first, and the actor's resistances, vulnerabilities and immunities
+// then apply once to each type's total, as in D&D 5e. Temporary HP absorb the
+// damage first, and HP will not go below 0.
+//
+// Example:
+//
+//	// Flame tongue longsword against a fire elemental
+//	report := elemental.ApplyDamage(
+//		d20.Damage{Amount: 6, Type: d20.Slashing},
+//		d20.Damage{Amount: 9, Type: d20.Fire},
+//	)
+//	fmt.Println(report.Detail) // Took 3 damage... 3 slashing (resisted, 6 rolled), 0 fire (immune, 9 rolled); *HP: 99*
+func (a *Actor) ApplyDamage(damage ...Damage) DamageReport {
+	// Total each type, keeping the order the types first appear in
+	var totals []Damage
+	for _, d := range damage {
+		d.Type = d.Type.normalize()
+		i := slices.IndexFunc(totals, func(t Damage) bool { return t.Type == d.Type })
+		if i < 0 {
+			totals = append(totals, d)
+		} else {
+			totals[i].Amount += d.Amount
+		}
+	}
+
+	var report DamageReport
+	parts := make([]string, 0, len(totals))
+	for _, d := range totals {
+		result := a.dealt(d)
+		report.Components = append(report.Components, result)
+		report.Total += result.Dealt
+		parts = append(parts, result.String())
+	}
+
+	report.Absorbed = a.takeDamage(report.Total)
+	report.HP, report.TempHP = a.currentHP, a.tempHP
+
+	report.Detail = fmt.Sprintf("Took %d damage... %s", report.Total, strings.Join(parts, ", "))
+	if report.Absorbed > 0 {
+		report.Detail += fmt.Sprintf("; %d absorbed by temp HP", report.Absorbed)
+	}
+	hp := fmt.Sprintf("%d", report.HP)
+	if report.TempHP > 0 {
+		hp += fmt.Sprintf(" (+%d temp)", report.TempHP)
+	}
+	report.Detail += "; *HP: " + hp + "*"
+	return report
+}
+
+// AddResistance makes the actor take half damage from the given types.
+func (a *Actor) AddResistance(types ...DamageType) {
+	a.addDamageTraits(resistant, types)
+}
+
+// AddVulnerability makes the actor take double damage from the given types.
+func (a *Actor) AddVulnerability(types ...DamageType) {
+	a.addDamageTraits(vulnerable, types)
+}
+
+// AddImmunity makes the actor take no damage from the given types.
+func (a *Actor) AddImmunity(types ...DamageType) {
+	a.addDamageTraits(immune, types)
+}
+
+// RemoveResistance removes resistance to the given types.
+func (a *Actor) RemoveResistance(types ...DamageType) {
+	a.removeDamageTraits(resistant, types)
+}
+
+// RemoveVulnerability removes vulnerability to the given types.
+func (a *Actor) RemoveVulnerability(types ...DamageType) {
+	a.removeDamageTraits(vulnerable, types)
+}
+
+// RemoveImmunity removes immunity to the given types.
+func (a *Actor) RemoveImmunity(types ...DamageType) {
+	a.removeDamageTraits(immune, types)
+}
+
+// IsResistant reports whether the actor resists the damage type.
+func (a *Actor) IsResistant(t DamageType) bool {
+	return a.damageTraits[t.normalize()]&resistant != 0
+}
+
+// IsVulnerable reports whether the actor is vulnerable to the damage type.
+func (a *Actor) IsVulnerable(t DamageType) bool {
+	return a.damageTraits[t.normalize()]&vulnerable != 0
+}
+
+// IsImmune reports whether the actor is immune to the damage type.
+func (a *Actor) IsImmune(t DamageType) bool {
+	return a.damageTraits[t.normalize()]&immune != 0
+}
+
+func (a *Actor) addDamageTraits(trait damageTraits, types []DamageType) {
+	if a.damageTraits == nil {
+		a.damageTraits = make(map[DamageType]damageTraits)
+	}
+	for _, t := range types {
+		a.damageTraits[t.normalize()] |= trait
+	}
+}
+
+func (a *Actor) removeDamageTraits(trait damageTraits, types []DamageType) {
+	for _, t := range types {
+		t = t.normalize()
+		if remaining := a.damageTraits[t] &^ trait; remaining != 0 {
+			a.damageTraits[t] = remaining
+		} else {
+			delete(a.damageTraits, t)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package d20
+
+import "testing"
+
+func TestActor_ApplyDamage(t *testing.T) {
+	newElemental := func() *Actor {
+		elemental, err := NewActor("fire elemental").
+			WithHP(102).
+			WithResistances(Slashing, Piercing).
+			WithVulnerabilities(Cold, Piercing).
+			WithImmunities(Fire, "Poison").
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error: %v", err)
+		}
+		return elemental
+	}
+
+	tests := []struct {
+		name       string
+		damage     []Damage
+		wantDealt  []int
+		wantTotal  int
+		wantDetail string
+	}{
+		{
+			name:       "Untyped damage",
+			damage:     []Damage{{Amount: 7}},
+			wantDealt:  []int{7},
+			wantTotal:  7,
+			wantDetail: "Took 7 damage... 7; *HP: 95*",
+		},
+		{
+			name:       "Resistance rounds down",
+			damage:     []Damage{{Amount: 7, Type: Slashing}},
+			wantDealt:  []int{3},
+			wantTotal:  3,
+			wantDetail: "Took 3 damage... 3 slashing (resisted, 7 rolled); *HP: 99*",
+		},
+		{
+			name:       "Same type is totaled before resistance",
+			damage:     []Damage{{Amount: 5, Type: Slashing}, {Amount: 3, Type: Fire}, {Amount: 3, Type: "SLASHING"}},
+			wantDealt:  []int{4, 0},
+			wantTotal:  4,
+			wantDetail: "Took 4 damage... 4 slashing (resisted, 8 rolled), 0 fire (immune, 3 rolled); *HP: 98*",
+		},
+		{
+			name:       "Immunity and vulnerability in one hit",
+			damage:     []Damage{{Amount: 9, Type: Fire}, {Amount: 4, Type: Cold}, {Amount: 3, Type: Bludgeoning}},
+			wantDealt:  []int{0, 8, 3},
+			wantTotal:  11,
+			wantDetail: "Took 11 damage... 0 fire (immune, 9 rolled), 8 cold (vulnerable, 4 rolled), 3 bludgeoning; *HP: 91*",
+		},
+		{
+			name:      "Resistance applies before vulnerability",
+			damage:    []Damage{{Amount: 7, Type: Piercing}},
+			wantDealt: []int{6},
+			wantTotal: 6,
+		},
+		{
+			name:      "Types are case insensitive",
+			damage:    []Damage{{Amount: 5, Type: "POISON"}},
+			wantDealt: []int{0},
+			wantTotal: 0,
+		},
+		{
+			name:      "Negative damage deals nothing",
+			damage:    []Damage{{Amount: -2, Type: Acid}},
+			wantDealt: []int{0},
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elemental := newElemental()
+			report := elemental.ApplyDamage(tt.damage...)
+			if len(report.Components) != len(tt.wantDealt) {
+				t.Fatalf("%d components, want %d", len(report.Components), len(tt.wantDealt))
+			}
+			for i, component := range report.Components {
+				if component.Dealt != tt.wantDealt[i] {
+					t.Errorf("component %d dealt %d, want %d", i, component.Dealt, tt.wantDealt[i])
+				}
+			}
+			if report.Total != tt.wantTotal || elemental.HP() != 102-tt.wantTotal || report.HP != elemental.HP() {
+				t.Errorf("Total = %d, HP = %d; want %d, %d", report.Total, elemental.HP(), tt.wantTotal, 102-tt.wantTotal)
+			}
+			if tt.wantDetail != "" && report.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", report.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestActor_DamageTraits(t *testing.T) {
+	actor, err := NewActor("druid").WithHP(10).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	actor.AddResistance(Fire, Cold)
+	actor.AddVulnerability(Fire)
+	actor.AddImmunity(Poison)
+	if !actor.IsResistant("fire") || !actor.IsVulnerable(Fire) || !actor.IsImmune(Poison) {
+		t.Error("added traits should be reported")
+	}
+
+	actor.RemoveResistance(Fire)
+	actor.RemoveImmunity(Poison)
+	actor.RemoveVulnerability(Thunder)
+	if actor.IsResistant(Fire) || !actor.IsVulnerable(Fire) || actor.IsImmune(Poison) || !actor.IsResistant(Cold) {
+		t.Error("removing a trait should leave the others")
+	}
+
+	if report := actor.ApplyDamage(Damage{Amount: 30, Type: Fire}); report.HP != 0 || actor.HP() != 0 {
+		t.Errorf("HP = %d, should not go below 0", actor.HP())
+	}
+}
+
+func TestActor_ApplyDamageTempHP(t *testing.T) {
+	actor, err := NewActor("paladin").WithHP(30).WithTempHP(6).WithResistances(Fire).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	report := actor.ApplyDamage(Damage{Amount: 4, Type: Fire})
+	if report.Absorbed != 2 || report.TempHP != 4 || report.HP != 30 {
+		t.Errorf("Absorbed = %d, TempHP = %d, HP = %d; want 2, 4, 30", report.Absorbed, report.TempHP, report.HP)
+	}
+	if want := "Took 2 damage... 2 fire (resisted, 4 rolled); 2 absorbed by temp HP; *HP: 30 (+4 temp)*"; report.Detail != want {
+		t.Errorf("Detail = %q, want %q", report.Detail, want)
+	}
+
+	report = actor.ApplyDamage(Damage{Amount: 10, Type: Slashing})
+	if report.Absorbed != 4 || report.TempHP != 0 || report.HP != 24 {
+		t.Errorf("Absorbed = %d, TempHP = %d, HP = %d; want 4, 0, 24", report.Absorbed, report.TempHP, report.HP)
+	}
+}
@@ -0,0 +1,284 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var errInvalidKeep = errors.New("keep/drop count must be at least 1 and leave at least one die")
+
+// keepMode selects which dice of a term count toward its total.
+type keepMode int
+
+const (
+	keepAll     keepMode = iota // Every die counts
+	keepHighest                 // Keep the highest n dice ("kh")
+	keepLowest                  // Keep the lowest n dice ("kl")
+	dropHighest                 // Drop the highest n dice ("dh")
+	dropLowest                  // Drop the lowest n dice ("dl")
+)
+
+// keepNotation maps notation suffixes to keep modes.
+var keepNotation = map[string]keepMode{
+	"kh": keepHighest,
+	"kl": keepLowest,
+	"dh": dropHighest,
+	"dl": dropLowest,
+}
+
+// keepRule describes a keep/drop rule such as "kh3".
+type keepRule struct {
+	mode keepMode
+	n    uint
+}
+
+// validate checks the rule against the number of dice it applies to.
+func (k keepRule) validate(rollCount uint) error {
+	switch k.mode {
+	case keepAll:
+		return nil
+	case keepHighest, keepLowest:
+		if k.n == 0 || k.n > rollCount {
+			return fmt.Errorf("%w: cannot keep %d of %d dice", errInvalidKeep, k.n, rollCount)
+		}
+	case dropHighest, dropLowest:
+		if k.n == 0 || k.n >= rollCount {
+			return fmt.Errorf("%w: cannot drop %d of %d dice", errInvalidKeep, k.n, rollCount)
+		}
+	}
+	return nil
+}
+
+// String formats the rule as a notation suffix, e.g. "kh3".
+func (k keepRule) String() string {
+	for suffix, mode := range keepNotation {
+		if mode == k.mode {
+			return fmt.Sprintf("%s%d", suffix, k.n)
+		}
+	}
+	return ""
+}
+
+// defaultExplosionLimit caps how many extra rolls a single die may explode
+// into when no limit is configured.
+const defaultExplosionLimit = 100
+
+// explodeMode selects how a die that explodes adds its extra rolls.
+type explodeMode int
+
+const (
+	explodeNone      explodeMode = iota // Dice do not explode
+	explodeStandard                     // Extra rolls are added as new dice ("!")
+	explodeCompound                     // Extra rolls are added into the same die ("!!")
+	explodePenetrate                    // Extra rolls are new dice with 1 subtracted ("!p")
+)
+
+// explodeNotation maps explode modes to their notation prefixes.
+var explodeNotation = map[explodeMode]string{
+	explodeStandard:  "!",
+	explodeCompound:  "!!",
+	explodePenetrate: "!p",
+}
+
+// explodeRule describes when and how dice explode.
+type explodeRule struct {
+	mode  explodeMode
+	on    Condition // Faces that explode; the zero Condition means the maximum face
+	limit uint      // Maximum extra rolls per die; zero means defaultExplosionLimit
+}
+
+// condition returns the condition that triggers an explosion on a die whose
+// highest face is maxFace.
+func (e explodeRule) condition(maxFace int) Condition {
+	if e.on.IsZero() {
+		return Equals(maxFace)
+	}
+	return e.on
+}
+
+// maxExplosions returns the cap on extra rolls per die.
+func (e explodeRule) maxExplosions() uint {
+	if e.limit == 0 {
+		return defaultExplosionLimit
+	}
+	return e.limit
+}
+
+// String formats the rule as a notation suffix, e.g. "!" or "!p>=5".
+func (e explodeRule) String() string {
+	if e.mode == explodeNone {
+		return ""
+	}
+	return explodeNotation[e.mode] + e.on.String()
+}
+
+// defaultRerollLimit caps how many times a die may be rerolled by a
+// reroll-until rule, so conditions matching every face still terminate.
+const defaultRerollLimit = 100
+
+// rerollRule describes which dice are rerolled.
+type rerollRule struct {
+	on   Condition // Faces that are rerolled; the zero Condition disables rerolls
+	once bool      // Reroll at most once, keeping the new value even if it matches
+}
+
+// String formats the rule as a notation suffix, e.g. "ro<=2".
+func (r rerollRule) String() string {
+	switch {
+	case r.on.IsZero():
+		return ""
+	case r.once:
+		return "ro" + r.on.String()
+	default:
+		return "r" + r.on.String()
+	}
+}
+
+// rollDice rolls the dice of a term, applying advantage or disadvantage per
+// die, rerolls, explosions, and then the keep rule. Returns every die rolled and the
+// total of the dice that were kept.
+func (r *Roller) rollDice(n *diceNode, advantageType AdvantageType) ([]DieResult, int) {
+	var dice []DieResult
+
+	switch advantageType {
+	case Normal:
+		// Roll normally - one roll per die
+		for range n.count {
+			dice = append(dice, r.rollDie(n)...)
+		}
+
+	case Advantage, Disadvantage:
+		// Roll twice per die, keep all rolls but drop the lower (advantage)
+		// or higher (disadvantage) of each pair. Dice added by explosions
+		// count toward their pair and are dropped with it.
+		// For 1d20 with advantage: rolls = [17, 12], used 17
+		for range n.count {
+			first := r.rollDie(n)
+			second := r.rollDie(n)
+			var dropSecond bool
+			if advantageType == Advantage {
+				dropSecond = sumDice(second) <= sumDice(first)
+			} else {
+				dropSecond = sumDice(second) >= sumDice(first)
+			}
+			markDropped(first, !dropSecond)
+			markDropped(second, dropSecond)
+			dice = append(dice, first...)
+			dice = append(dice, second...)
+		}
+	}
+
+	applyKeep(dice, n.keep)
+
+	total := 0
+	for _, die := range dice {
+		if !die.Dropped {
+			total += die.Value
+		}
+	}
+	return dice, total
+}
+
+// rollDie rolls a single die of the term along with any extra dice it
+// explodes into. Reroll rules apply to the die's initial roll. The explosion
+// condition is tested against the raw face rolled, so penetrating dice keep
+// exploding on their maximum face.
+func (r *Roller) rollDie(n *diceNode) []DieResult {
+	dieFaces, explode := n.faces, n.explode
+
+	raw := r.rollFace(n)
+	die := DieResult{Faces: dieFaces, Kind: n.kind, Value: raw, Raw: raw}
+	for i := 0; i < defaultRerollLimit && n.reroll.on.Matches(raw); i++ {
+		die.Rerolled = append(die.Rerolled, raw)
+		raw = r.rollFace(n)
+		die.Value, die.Raw = raw, raw
+		if n.reroll.once {
+			break
+		}
+	}
+
+	dice := []DieResult{die}
+	if explode.mode == explodeNone {
+		return dice
+	}
+
+	on := explode.condition(n.maxFace())
+	for i := uint(0); i < explode.maxExplosions() && on.Matches(raw); i++ {
+		raw = r.rollFace(n)
+		switch explode.mode {
+		case explodeCompound:
+			dice[0].Value += raw
+			dice[0].Compounded++
+		case explodePenetrate:
+			dice = append(dice, DieResult{Faces: dieFaces, Kind: n.kind, Value: raw - 1, Raw: raw, Exploded: true})
+		default:
+			dice = append(dice, DieResult{Faces: dieFaces, Kind: n.kind, Value: raw, Raw: raw, Exploded: true})
+		}
+	}
+	return dice
+}
+
+// rollFace draws a single face value for a die of the term's kind.
+func (r *Roller) rollFace(n *diceNode) int {
+	switch n.kind {
+	case FateDie:
+		return r.source.IntN(3) - 1
+	case D66Die:
+		tens := r.source.IntN(6) + 1
+		ones := r.source.IntN(6) + 1
+		return tens*10 + ones
+	}
+	return r.source.IntN(int(n.faces)) + 1
+}
+
+// sumDice totals the values of the given dice.
+func sumDice(dice []DieResult) int {
+	total := 0
+	for _, die := range dice {
+		total += die.Value
+	}
+	return total
+}
+
+// markDropped sets the dropped flag on every die in the slice.
+func markDropped(dice []DieResult, dropped bool) {
+	for i := range dice {
+		dice[i].Dropped = dropped
+	}
+}
+
+// applyKeep marks dice as dropped according to the keep rule. Only dice that
+// are still kept are considered. Ties between equal values follow die order.
+func applyKeep(dice []DieResult, keep keepRule) {
+	if keep.mode == keepAll {
+		return
+	}
+
+	var candidates []int
+	for i, die := range dice {
+		if !die.Dropped {
+			candidates = append(candidates, i)
+		}
+	}
+	// Order from highest to lowest value
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return dice[candidates[a]].Value > dice[candidates[b]].Value
+	})
+
+	n := int(min(keep.n, uint(len(candidates))))
+	var drop []int
+	switch keep.mode {
+	case keepHighest:
+		drop = candidates[n:]
+	case keepLowest:
+		drop = candidates[:len(candidates)-n]
+	case dropHighest:
+		drop = candidates[:n]
+	case dropLowest:
+		drop = candidates[len(candidates)-n:]
+	}
+	for _, i := range drop {
+		dice[i].Dropped = true
+	}
+}
@@ -0,0 +1,18 @@
+package d20
+
+// DieKind identifies how a die's faces are numbered.
+// Most dice are StandardDie, numbered 1 through their face count.
+type DieKind int
+
+const (
+	StandardDie DieKind = iota // Faces numbered 1 to N
+	FateDie                    // Fate/Fudge die with faces -1, 0 and +1 ("dF")
+	D66Die                     // Two d6 read as tens and ones, giving 11 to 66 ("d66")
+)
+
+// fateSymbols renders Fate die values in roll details.
+var fateSymbols = map[int]string{
+	-1: "[-]",
+	0:  "[ ]",
+	1:  "[+]",
+}
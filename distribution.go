@@ -0,0 +1,741 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var errUnsupportedDistribution = errors.New("exact distribution is not supported for this roll")
+
+// negligibleProbability is the chance below which the distribution of an
+// exploding die stops following further explosions.
+const negligibleProbability = 1e-15
+
+// smallestNormal is the smallest positive normal float64. Probabilities below
+// it are dropped, since subnormal arithmetic is slow and imprecise.
+const smallestNormal = 0x1p-1022
+
+// maxDistributionFaces is the largest die that can be analyzed. Every face
+// is a separate result, so larger dice cost too much time and memory.
+const maxDistributionFaces = 1 << 16
+
+// Distribution is the exact probability distribution of a roll's result,
+// computed analytically without rolling any dice.
+// Create one with Analyze or RollBuilder.Distribution.
+type Distribution struct {
+	min   int       // Lowest result with non-zero probability
+	probs []float64 // probs[i] is the probability of the result min+i
+}
+
+// Analyze returns the exact probability distribution of a roll described by
+// dice notation. Accepts the same notation as Roller.Roll.
+//
+// Example:
+//
+//	dist, _ := d20.Analyze("2d6+3")
+//	fmt.Printf("%.1f\n", dist.Mean()) // 10.0
+func Analyze(notation string) (Distribution, error) {
+	// Parsing never rolls dice, so the roller needs no random source
+	builder, err := (&Roller{}).Notation(notation)
+	if err != nil {
+		return Distribution{}, err
+	}
+	return builder.Distribution()
+}
+
+// Distribution returns the exact probability distribution of the configured
+// roll, including advantage/disadvantage, flat and dice modifiers, and critical
+// damage and multipliers, without rolling.
+// Dice terms are combined by convolution, so large pools such as "1000d6" stay fast.
+// Exploding dice are followed until the chance of exploding again is
+// negligible, and results too unlikely to represent as a float64 are dropped.
+//
+// Returns an error for rolls that can't be analyzed exactly: keep/drop rules
+// on dice that explode into extra dice, advantage on exploding
+// success-counting pools, stacking policies with dice modifiers, and dice
+// with more than 65536 faces.
+//
+// Example:
+//
+//	dist, _ := roller.Dice(1, 20).WithAdvantage().WithModifier("attack", 5).Distribution()
+//	fmt.Printf("%.3f\n", dist.ProbabilityAtLeast(15))
+func (rb *RollBuilder) Distribution() (Distribution, error) {
+	if err := rb.validateDamage(); err != nil {
+		return Distribution{}, err
+	}
+	expr, primary := rb.damageExpr()
+	an := &analysis{primary: primary, advantage: rb.advantage()}
+	dist, err := expr.distribution(an)
+	if err != nil {
+		return Distribution{}, err
+	}
+
+	modifiers := rb.damageModifiers()
+	if rb.stacking != nil {
+		if slices.ContainsFunc(modifiers, Modifier.IsDice) {
+			return Distribution{}, fmt.Errorf("%w: stacking rules with dice modifiers", errUnsupportedDistribution)
+		}
+		modifiers, _ = rb.stack(modifiers)
+	}
+
+	modifierTotal := 0
+	for _, mod := range modifiers {
+		if !mod.IsDice() {
+			modifierTotal += mod.Value
+			continue
+		}
+		expr, err := parseNotation(mod.Dice)
+		if err != nil {
+			return Distribution{}, err
+		}
+		modDist, err := expr.distribution(&analysis{advantage: Normal})
+		if err != nil {
+			return Distribution{}, err
+		}
+		dist = dist.add(modDist)
+	}
+	dist = dist.shift(modifierTotal)
+
+	switch rb.critDamage {
+	case MaxPlusRoll:
+		dist = dist.shift(maxDice(expr) + maxDiceModifiers(modifiers))
+	case DoubleTotal:
+		dist = dist.mapValues(func(v int) int { return v * 2 })
+	}
+	for _, m := range rb.multipliers {
+		dist = dist.mapValues(m.apply)
+	}
+	return dist, nil
+}
+
+// Min returns the lowest possible result.
+func (d Distribution) Min() int {
+	return d.min
+}
+
+// Max returns the highest possible result.
+func (d Distribution) Max() int {
+	return d.min + len(d.probs) - 1
+}
+
+// Probability returns the probability of the result being exactly value.
+func (d Distribution) Probability(value int) float64 {
+	i := value - d.min
+	if i < 0 || i >= len(d.probs) {
+		return 0
+	}
+	return d.probs[i]
+}
+
+// CDF returns the probability of the result being value or lower.
+func (d Distribution) CDF(value int) float64 {
+	total := 0.0
+	for i, p := range d.probs {
+		if d.min+i > value {
+			break
+		}
+		total += p
+	}
+	return min(total, 1)
+}
+
+// ProbabilityAtLeast returns the probability of the result being value or
+// higher, such as the chance of meeting a DC.
+func (d Distribution) ProbabilityAtLeast(value int) float64 {
+	return max(1-d.CDF(value-1), 0)
+}
+
+// PMF returns the probability mass function as a map from each possible
+// result to its probability. Results that can't occur are omitted.
+func (d Distribution) PMF() map[int]float64 {
+	pmf := make(map[int]float64, len(d.probs))
+	for i, p := range d.probs {
+		if p > 0 {
+			pmf[d.min+i] = p
+		}
+	}
+	return pmf
+}
+
+// Outcomes returns every possible result in ascending order.
+func (d Distribution) Outcomes() []int {
+	var outcomes []int
+	for i, p := range d.probs {
+		if p > 0 {
+			outcomes = append(outcomes, d.min+i)
+		}
+	}
+	return outcomes
+}
+
+// Mean returns the expected result.
+func (d Distribution) Mean() float64 {
+	mean := 0.0
+	for i, p := range d.probs {
+		mean += float64(d.min+i) * p
+	}
+	return mean
+}
+
+// Variance returns the variance of the result.
+func (d Distribution) Variance() float64 {
+	mean := d.Mean()
+	variance := 0.0
+	for i, p := range d.probs {
+		diff := float64(d.min+i) - mean
+		variance += diff * diff * p
+	}
+	return variance
+}
+
+// StdDev returns the standard deviation of the result.
+func (d Distribution) StdDev() float64 {
+	return math.Sqrt(d.Variance())
+}
+
+// analysis carries the roll configuration through a distribution computation.
+type analysis struct {
+	primary   *diceNode     // Dice term that advantage/disadvantage applies to
+	advantage AdvantageType // Advantage state for the primary term
+}
+
+func (n *numberNode) distribution(*analysis) (Distribution, error) {
+	return pointDistribution(n.value), nil
+}
+
+func (n *binaryNode) distribution(an *analysis) (Distribution, error) {
+	left, err := n.left.distribution(an)
+	if err != nil {
+		return Distribution{}, err
+	}
+	right, err := n.right.distribution(an)
+	if err != nil {
+		return Distribution{}, err
+	}
+
+	switch n.op {
+	case '+':
+		return left.add(right), nil
+	case '-':
+		return left.add(right.negate()), nil
+	case '*':
+		return left.combine(right, func(a, b int) int { return a * b }), nil
+	case '/':
+		if right.Probability(0) > 0 {
+			return Distribution{}, errDivisionByZero
+		}
+		return left.combine(right, floorDiv), nil
+	}
+	return Distribution{}, errUnsupportedDistribution
+}
+
+func (n *negateNode) distribution(an *analysis) (Distribution, error) {
+	dist, err := n.operand.distribution(an)
+	return dist.negate(), err
+}
+
+func (n *groupNode) distribution(an *analysis) (Distribution, error) {
+	return n.inner.distribution(an)
+}
+
+// distribution computes the term's total in three steps: the distribution of
+// a single die unit (a die with its rerolls and explosions), the unit after
+// advantage/disadvantage, and finally the keep rule or a sum over every die.
+func (n *diceNode) distribution(an *analysis) (Distribution, error) {
+	if err := n.validate(); err != nil {
+		return Distribution{}, err
+	}
+	if n.faces > maxDistributionFaces {
+		return Distribution{}, fmt.Errorf("%w: dice with more than %d faces", errUnsupportedDistribution, maxDistributionFaces)
+	}
+
+	advantage := Normal
+	if n == an.primary {
+		advantage = an.advantage
+	}
+
+	// Dice that explode into extra dice have no single value to compare
+	// or keep, except when only their sum matters
+	chained := n.explode.mode == explodeStandard || n.explode.mode == explodePenetrate
+	if chained && n.keep.mode != keepAll {
+		return Distribution{}, errUnsupportedDistribution
+	}
+	score := func(v int) int { return v }
+	if n.pool.counts() {
+		if chained && advantage != Normal {
+			return Distribution{}, errUnsupportedDistribution
+		}
+		score = n.pool.successes
+	}
+
+	unit := n.unitDistribution(score)
+	switch advantage {
+	case Advantage:
+		unit = unit.maxOfTwo()
+	case Disadvantage:
+		unit = unit.minOfTwo()
+	}
+	if !chained {
+		// Each unit is one die, so successes are scored from its value
+		if n.keep.mode != keepAll {
+			return unit.keep(n.count, n.keep, score), nil
+		}
+		unit = unit.mapValues(score)
+	}
+
+	return unit.times(n.count), nil
+}
+
+// unitDistribution returns the distribution of a single die with its rerolls
+// and explosions. Dice that explode into extra dice are scored per die, so
+// the result is their total score; other dice return their unscored value.
+func (n *diceNode) unitDistribution(score func(int) int) Distribution {
+	face := n.faceDistribution()
+	rolled := face.reroll(n.reroll)
+	if n.explode.mode == explodeNone {
+		return rolled
+	}
+
+	// tail is the distribution of the total added by the extra rolls that
+	// follow an exploding face, built from the deepest allowed explosion up
+	on := n.explode.condition(n.maxFace())
+	extraValue := func(r int) int {
+		switch n.explode.mode {
+		case explodePenetrate:
+			return score(r - 1)
+		case explodeCompound:
+			return r
+		}
+		return score(r)
+	}
+	tail := pointDistribution(0)
+	for range n.explosionDepth(face, on) {
+		weights := make(map[int]float64)
+		for r, p := range face.PMF() {
+			if on.Matches(r) {
+				tail.addWeighted(weights, p, extraValue(r))
+			} else {
+				weights[extraValue(r)] += p
+			}
+		}
+		tail = newDistribution(weights)
+	}
+
+	firstValue := score
+	if n.explode.mode == explodeCompound {
+		firstValue = func(v int) int { return v }
+	}
+	weights := make(map[int]float64)
+	for r, p := range rolled.PMF() {
+		if on.Matches(r) {
+			tail.addWeighted(weights, p, firstValue(r))
+		} else {
+			weights[firstValue(r)] += p
+		}
+	}
+	return newDistribution(weights)
+}
+
+// explosionDepth returns how many extra rolls the distribution of an
+// exploding die follows: the die's explosion cap, or fewer once the chance of
+// exploding that many times in a row drops below negligibleProbability.
+func (n *diceNode) explosionDepth(face Distribution, on Condition) uint {
+	explodes := 0.0
+	for r, p := range face.PMF() {
+		if on.Matches(r) {
+			explodes += p
+		}
+	}
+
+	depth := uint(0)
+	for reach := 1.0; depth < n.explode.maxExplosions() && reach >= negligibleProbability; depth++ {
+		reach *= explodes
+	}
+	return depth
+}
+
+// faceDistribution returns the distribution of a single roll of the term's die.
+func (n *diceNode) faceDistribution() Distribution {
+	switch n.kind {
+	case FateDie:
+		return Distribution{min: -1, probs: []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}}
+	case D66Die:
+		weights := make(map[int]float64)
+		for tens := 1; tens <= 6; tens++ {
+			for ones := 1; ones <= 6; ones++ {
+				weights[tens*10+ones] = 1.0 / 36
+			}
+		}
+		return newDistribution(weights)
+	}
+	probs := make([]float64, n.faces)
+	for i := range probs {
+		probs[i] = 1 / float64(n.faces)
+	}
+	return Distribution{min: 1, probs: probs}
+}
+
+// reroll returns the distribution of a die with distribution d after the
+// reroll rule is applied. Each reroll draws a fresh value from d.
+func (d Distribution) reroll(rule rerollRule) Distribution {
+	if rule.on.IsZero() {
+		return d
+	}
+	face := d
+	limit := defaultRerollLimit
+	if rule.once {
+		limit = 1
+	}
+
+	for range limit {
+		weights := make(map[int]float64)
+		rerolled := 0.0
+		for v, p := range d.PMF() {
+			if rule.on.Matches(v) {
+				rerolled += p
+			} else {
+				weights[v] += p
+			}
+		}
+		if rerolled == 0 {
+			break
+		}
+		face.addWeighted(weights, rerolled, 0)
+		d = newDistribution(weights)
+	}
+	return d
+}
+
+// keep returns the distribution of the total score of the kept dice when
+// count independent dice with distribution d are rolled.
+//
+// Values are ordered from the first kept end (highest for keep highest), and
+// the outcomes are split by the last value kept, v. If a of the kept dice show
+// a value before v, the other kept dice all show v, and the a dice are
+// independent draws from d restricted to the values before v. Their total is
+// an a-fold sum built up one die at a time, which avoids enumerating every
+// combination of dice.
+func (d Distribution) keep(count uint, rule keepRule, score func(int) int) Distribution {
+	n := int(count)
+	k := int(rule.n)
+	highest := true
+	switch rule.mode {
+	case keepLowest:
+		highest = false
+	case dropHighest:
+		k, highest = n-k, false
+	case dropLowest:
+		k = n - k
+	}
+	if k <= 0 {
+		return pointDistribution(0)
+	}
+
+	values := d.Outcomes()
+	if highest {
+		slices.Reverse(values)
+	}
+	// after[j] is the probability of a die showing values[j] or a later value
+	after := make([]float64, len(values)+1)
+	for j := len(values) - 1; j >= 0; j-- {
+		after[j] = after[j+1] + d.Probability(values[j])
+	}
+
+	// kept accumulates the result over every score k dice could total
+	lo, hi := score(values[0]), score(values[0])
+	for _, v := range values {
+		lo, hi = min(lo, score(v)), max(hi, score(v))
+	}
+	kept := Distribution{min: k * lo, probs: make([]float64, k*(hi-lo)+1)}
+
+	binomial := newBinomial(n)
+	for j, v := range values {
+		before := max(1-after[j], 0)
+		q := min(d.Probability(v)/after[j], 1) // Chance a die not before v shows v
+
+		// sum is the total score of the a dice drawn from the values before v
+		var unit Distribution
+		if j > 0 {
+			restricted := make(map[int]float64)
+			for _, u := range values[:j] {
+				restricted[score(u)] += d.Probability(u) / before
+			}
+			unit = newDistribution(restricted)
+		}
+		sum := pointDistribution(0)
+		for a := range k {
+			if a > 0 {
+				if j == 0 {
+					break // No value comes before the first
+				}
+				sum = sum.add(unit)
+			}
+			w := binomial.probability(n, a, before) * binomial.atLeast(n-a, k-a, q)
+			if w < smallestNormal {
+				continue
+			}
+			offset := sum.min + (k-a)*score(v) - kept.min
+			for i, p := range sum.probs {
+				kept.probs[offset+i] += w * p
+			}
+		}
+	}
+	return kept.trim()
+}
+
+// pointDistribution returns a distribution that is always value.
+func pointDistribution(value int) Distribution {
+	return Distribution{min: value, probs: []float64{1}}
+}
+
+// newDistribution builds a distribution from probability weights by result.
+// Returns the zero Distribution if no result has a positive weight.
+func newDistribution(weights map[int]float64) Distribution {
+	lo, hi := math.MaxInt, math.MinInt
+	for v, p := range weights {
+		if p > 0 {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if lo > hi {
+		return Distribution{}
+	}
+
+	probs := make([]float64, hi-lo+1)
+	for v, p := range weights {
+		if p > 0 {
+			probs[v-lo] += p
+		}
+	}
+	return Distribution{min: lo, probs: probs}
+}
+
+// times returns the distribution of the sum of count independent results.
+// Adding a uniform result takes a single pass, so plain dice are added one at
+// a time; other results are summed by repeated squaring.
+func (d Distribution) times(count uint) Distribution {
+	total := pointDistribution(0)
+	if d.uniform() {
+		for range count {
+			total = total.add(d)
+		}
+		return total
+	}
+	for ; count > 0; count >>= 1 {
+		if count&1 == 1 {
+			total = total.add(d)
+		}
+		if count > 1 {
+			d = d.add(d)
+		}
+	}
+	return total
+}
+
+// addWeighted adds the distribution, shifted by offset and scaled by weight,
+// into weights.
+func (d Distribution) addWeighted(weights map[int]float64, weight float64, offset int) {
+	for i, p := range d.probs {
+		if p > 0 {
+			weights[d.min+i+offset] += weight * p
+		}
+	}
+}
+
+// shift returns the distribution with offset added to every result.
+func (d Distribution) shift(offset int) Distribution {
+	return Distribution{min: d.min + offset, probs: d.probs}
+}
+
+// negate returns the distribution of the negated result.
+func (d Distribution) negate() Distribution {
+	probs := slices.Clone(d.probs)
+	slices.Reverse(probs)
+	return Distribution{min: -d.Max(), probs: probs}
+}
+
+// add returns the distribution of the sum of two independent results.
+func (d Distribution) add(other Distribution) Distribution {
+	switch {
+	case other.uniform():
+		return d.addUniform(other)
+	case d.uniform():
+		return other.addUniform(d)
+	}
+
+	probs := make([]float64, len(d.probs)+len(other.probs)-1)
+	for i, p := range d.probs {
+		if p == 0 {
+			continue
+		}
+		// Skip the ends of other whose products with p would underflow,
+		// which are slow to compute and too small to represent
+		limit := smallestNormal / p
+		lo, hi := 0, len(other.probs)
+		for lo < hi && other.probs[lo] < limit {
+			lo++
+		}
+		for hi > lo && other.probs[hi-1] < limit {
+			hi--
+		}
+		sum := probs[i+lo : i+hi]
+		for j, q := range other.probs[lo:hi] {
+			sum[j] += p * q
+		}
+	}
+	return Distribution{min: d.min + other.min, probs: probs}.trim()
+}
+
+// addUniform returns the distribution of the sum of d and an independent
+// uniform result. Each result is a window of d's probabilities scaled by the
+// uniform probability, so the sum takes a single pass over d.
+func (d Distribution) addUniform(uniform Distribution) Distribution {
+	n, width, q := len(d.probs), len(uniform.probs), uniform.probs[0]
+
+	// Window sums are differences of running totals, taken from the left
+	// up to the most likely result and from the right after it, so small
+	// probabilities in either tail keep their precision
+	mode := 0
+	prefix := make([]float64, n+1)
+	for i, p := range d.probs {
+		prefix[i+1] = prefix[i] + p
+		if p > d.probs[mode] {
+			mode = i
+		}
+	}
+	suffix := make([]float64, n+1)
+	for i := n - 1; i >= 0; i-- {
+		suffix[i] = suffix[i+1] + d.probs[i]
+	}
+
+	probs := make([]float64, n+width-1)
+	for i := range probs {
+		lo, hi := max(i-width+1, 0), min(i, n-1)
+		if lo > mode {
+			probs[i] = (suffix[lo] - suffix[hi+1]) * q
+		} else {
+			probs[i] = (prefix[hi+1] - prefix[lo]) * q
+		}
+	}
+	return Distribution{min: d.min + uniform.min, probs: probs}.trim()
+}
+
+// uniform reports whether every result from Min to Max is equally likely,
+// as for a single plain die.
+func (d Distribution) uniform() bool {
+	if len(d.probs) < 2 {
+		return false
+	}
+	for _, p := range d.probs {
+		if p != d.probs[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// trim drops results from both ends whose probability is too small to
+// represent as a normal float64, such as the extremes of a large pool.
+// Subnormal probabilities would otherwise slow every later convolution.
+func (d Distribution) trim() Distribution {
+	lo, hi := 0, len(d.probs)
+	for lo < hi && d.probs[lo] < smallestNormal {
+		lo++
+	}
+	for hi > lo && d.probs[hi-1] < smallestNormal {
+		hi--
+	}
+	if lo == hi {
+		return Distribution{}
+	}
+	return Distribution{min: d.min + lo, probs: d.probs[lo:hi]}
+}
+
+// combine returns the distribution of op applied to two independent results.
+func (d Distribution) combine(other Distribution, op func(a, b int) int) Distribution {
+	weights := make(map[int]float64)
+	for i, p := range d.probs {
+		if p == 0 {
+			continue
+		}
+		for j, q := range other.probs {
+			if q > 0 {
+				weights[op(d.min+i, other.min+j)] += p * q
+			}
+		}
+	}
+	return newDistribution(weights)
+}
+
+// mapValues returns the distribution of fn applied to the result.
+func (d Distribution) mapValues(fn func(int) int) Distribution {
+	weights := make(map[int]float64)
+	for i, p := range d.probs {
+		if p > 0 {
+			weights[fn(d.min+i)] += p
+		}
+	}
+	return newDistribution(weights)
+}
+
+// maxOfTwo returns the distribution of the higher of two independent results.
+func (d Distribution) maxOfTwo() Distribution {
+	probs := make([]float64, len(d.probs))
+	below := 0.0
+	for i, p := range d.probs {
+		atMost := below + p
+		probs[i] = atMost*atMost - below*below
+		below = atMost
+	}
+	return Distribution{min: d.min, probs: probs}
+}
+
+// minOfTwo returns the distribution of the lower of two independent results.
+func (d Distribution) minOfTwo() Distribution {
+	return d.negate().maxOfTwo().negate()
+}
+
+// binomial computes binomial probabilities for up to n trials from a table
+// of log factorials.
+type binomial struct {
+	logFactorial []float64
+}
+
+func newBinomial(n int) binomial {
+	lf := make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		lf[i] = lf[i-1] + math.Log(float64(i))
+	}
+	return binomial{logFactorial: lf}
+}
+
+// probability returns the probability of exactly k successes in n
+// independent trials that each succeed with probability q.
+func (b binomial) probability(n, k int, q float64) float64 {
+	switch {
+	case q <= 0:
+		if k == 0 {
+			return 1
+		}
+		return 0
+	case q >= 1:
+		if k == n {
+			return 1
+		}
+		return 0
+	}
+	lf := b.logFactorial
+	return math.Exp(lf[n] - lf[k] - lf[n-k] + float64(k)*math.Log(q) + float64(n-k)*math.Log1p(-q))
+}
+
+// atLeast returns the probability of k or more successes in n independent
+// trials that each succeed with probability q.
+func (b binomial) atLeast(n, k int, q float64) float64 {
+	total := 0.0
+	for i := max(k, 0); i <= n; i++ {
+		total += b.probability(n, i, q)
+	}
+	return min(total, 1)
+}
@@ -0,0 +1,265 @@
+package d20
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const probabilityTolerance = 1e-9
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		notation string
+		min, max int
+		mean     float64
+		variance float64 // Checked when non-zero
+		probs    map[int]float64
+	}{
+		{"1d6", 1, 6, 3.5, 35.0 / 12, map[int]float64{1: 1.0 / 6, 6: 1.0 / 6}},
+		{"2d6", 2, 12, 7, 35.0 / 6, map[int]float64{7: 6.0 / 36, 2: 1.0 / 36}},
+		{"20d6", 20, 120, 70, 20 * 35.0 / 12, nil},
+		{"1d20+5", 6, 25, 15.5, 0, map[int]float64{6: 0.05}},
+		{"2d6+1d4-1", 2, 15, 8.5, 0, nil},
+		{"(1d4+1)*2", 4, 10, 7, 0, map[int]float64{5: 0}},
+		{"1d6/2", 0, 3, 1.5, 0, map[int]float64{0: 1.0 / 6, 3: 1.0 / 6}},
+		{"-1d4", -4, -1, -2.5, 0, nil},
+		{"4d6kh3", 3, 18, 15869.0 / 1296, 0, map[int]float64{18: 21.0 / 1296, 3: 1.0 / 1296}},
+		{"4d6dl1", 3, 18, 15869.0 / 1296, 0, nil},
+		{"2d20kl1", 1, 20, 7.175, 0, map[int]float64{1: 39.0 / 400}},
+		{"3d6dh2", 1, 6, 441.0 / 216, 0, nil},
+		{"1d6ro1", 1, 6, 141.0 / 36, 0, map[int]float64{1: 1.0 / 36, 2: 7.0 / 36}},
+		{"2d6ro<2", 2, 12, 25.0 / 3, 0, nil},
+		{"1d6r1", 1, 6, 4, 0, map[int]float64{1: 0, 2: 0.2}},
+		{"1d6!", 1, 126, 4.2, 0, map[int]float64{6: 0, 7: 1.0 / 36}},
+		{"1d6!!", 1, 126, 4.2, 0, nil},
+		{"1d6!p", 1, 106, 4, 0, map[int]float64{6: 1.0 / 36}},
+		{"1d6!>=5", 1, 198, 5.25, 0, nil},
+		{"4df", -4, 4, 0, 4.0 * 2 / 3, map[int]float64{0: 19.0 / 81, 4: 1.0 / 81}},
+		{"1d66", 11, 66, 38.5, 0, map[int]float64{11: 1.0 / 36, 17: 0}},
+		{"d%", 1, 100, 50.5, 0, nil},
+		{"10d10>=8", 0, 10, 3, 10 * 0.3 * 0.7, nil},
+		{"10d10>=8f1", -10, 10, 2, 0, nil},
+		{"4d10>=8dbl10", 0, 8, 1.6, 0, nil},
+		{"5d10kh2>=8", 0, 2, 0, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			dist, err := Analyze(tt.notation)
+			if err != nil {
+				t.Fatalf("Analyze(%q) error: %v", tt.notation, err)
+			}
+			if dist.Min() != tt.min || dist.Max() != tt.max {
+				t.Errorf("range = [%d, %d], want [%d, %d]", dist.Min(), dist.Max(), tt.min, tt.max)
+			}
+			if tt.mean != 0 && math.Abs(dist.Mean()-tt.mean) > probabilityTolerance {
+				t.Errorf("Mean() = %v, want %v", dist.Mean(), tt.mean)
+			}
+			if tt.variance != 0 && math.Abs(dist.Variance()-tt.variance) > probabilityTolerance {
+				t.Errorf("Variance() = %v, want %v", dist.Variance(), tt.variance)
+			}
+			for value, want := range tt.probs {
+				if got := dist.Probability(value); math.Abs(got-want) > probabilityTolerance {
+					t.Errorf("Probability(%d) = %v, want %v", value, got, want)
+				}
+			}
+			if total := dist.CDF(dist.Max()); math.Abs(total-1) > probabilityTolerance {
+				t.Errorf("probabilities sum to %v, want 1", total)
+			}
+		})
+	}
+}
+
+func TestAnalyze_LargeRolls(t *testing.T) {
+	tests := []struct {
+		notation string
+		mean     float64
+		variance float64 // Checked when non-zero
+	}{
+		{"1000d100", 50500, 1000 * (100*100 - 1) / 12.0},
+		{"10d100!", 10 * 50.5 * 100 / 99, 0},
+		{"1000d6!", 1000 * 4.2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			dist, err := Analyze(tt.notation)
+			if err != nil {
+				t.Fatalf("Analyze(%q) error: %v", tt.notation, err)
+			}
+			if math.Abs(dist.Mean()-tt.mean) > 1e-6*tt.mean {
+				t.Errorf("Mean() = %v, want %v", dist.Mean(), tt.mean)
+			}
+			if tt.variance != 0 && math.Abs(dist.Variance()-tt.variance) > 1e-6*tt.variance {
+				t.Errorf("Variance() = %v, want %v", dist.Variance(), tt.variance)
+			}
+			if total := dist.CDF(dist.Max()); math.Abs(total-1) > probabilityTolerance {
+				t.Errorf("probabilities sum to %v, want 1", total)
+			}
+		})
+	}
+
+	// The kept highest and lowest halves of a pool add up to the whole pool
+	highest, err := Analyze("1000d6kh500")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	lowest, err := Analyze("1000d6kl500")
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if sum := highest.Mean() + lowest.Mean(); math.Abs(sum-3500) > 1e-6 {
+		t.Errorf("kept halves average %v in total, want 3500", sum)
+	}
+}
+
+func TestRollBuilder_Distribution(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Advantage", func(t *testing.T) {
+		dist, err := roller.Dice(1, 20).WithAdvantage().Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if got, want := dist.Probability(20), 39.0/400; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("P(20) = %v, want %v", got, want)
+		}
+		if got, want := dist.Mean(), 13.825; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("Mean() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Disadvantage with modifiers", func(t *testing.T) {
+		dist, err := roller.Dice(1, 20).WithDisadvantage().WithModifier("attack", 5).Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if got, want := dist.Mean(), 12.175; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("Mean() = %v, want %v", got, want)
+		}
+		// Hitting AC 15 needs a 10 or higher on both dice
+		if got, want := dist.ProbabilityAtLeast(15), 0.55*0.55; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("ProbabilityAtLeast(15) = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Dice modifiers", func(t *testing.T) {
+		dist, err := roller.Dice(1, 20).WithModifier("strength", 3).WithDiceModifier("bless", "1d4").Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if dist.Min() != 5 || dist.Max() != 27 {
+			t.Errorf("range = %d-%d, want 5-27", dist.Min(), dist.Max())
+		}
+		if got, want := dist.Mean(), 16.0; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("Mean() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Cancelled advantage", func(t *testing.T) {
+		dist, err := roller.Dice(1, 20).WithAdvantage("flanking").WithDisadvantage("poisoned").Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if got, want := dist.Mean(), 10.5; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("Mean() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Advantage applies to the first term only", func(t *testing.T) {
+		builder, err := roller.Notation("1d20+1d4")
+		if err != nil {
+			t.Fatalf("Notation() error: %v", err)
+		}
+		dist, err := builder.WithAdvantage().Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if got, want := dist.Mean(), 13.825+2.5; math.Abs(got-want) > probabilityTolerance {
+			t.Errorf("Mean() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("PMF and outcomes", func(t *testing.T) {
+		dist, err := Analyze("1d4*2")
+		if err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+		want := []int{2, 4, 6, 8}
+		outcomes := dist.Outcomes()
+		if len(outcomes) != len(want) {
+			t.Fatalf("Outcomes() = %v, want %v", outcomes, want)
+		}
+		pmf := dist.PMF()
+		for i, v := range want {
+			if outcomes[i] != v || pmf[v] != 0.25 {
+				t.Errorf("outcome %d: got %d with probability %v", i, outcomes[i], pmf[v])
+			}
+		}
+		if len(pmf) != len(want) {
+			t.Errorf("PMF() has %d entries, want %d", len(pmf), len(want))
+		}
+	})
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     error
+	}{
+		{"1d6/(1d2-1)", errDivisionByZero},
+		{"4d6!kh3", errUnsupportedDistribution},
+		{"1d1000000000", errUnsupportedDistribution},
+		{"2d6+", errInvalidDiceNotation},
+		{"3", errInvalidDiceNotation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			if _, err := Analyze(tt.notation); !errors.Is(err, tt.want) {
+				t.Errorf("Analyze(%q) error = %v, want %v", tt.notation, err, tt.want)
+			}
+		})
+	}
+
+	pool := NewRoller(42).Dice(5, 10).Explode().CountSuccesses(8).WithAdvantage()
+	if _, err := pool.Distribution(); !errors.Is(err, errUnsupportedDistribution) {
+		t.Errorf("exploding pool with advantage: error = %v, want %v", err, errUnsupportedDistribution)
+	}
+}
+
+// TestDistribution_MatchesRolls checks the analytic mean against the mean of
+// many seeded rolls, so the engine stays in step with how dice are rolled.
+func TestDistribution_MatchesRolls(t *testing.T) {
+	const rolls = 5000
+	notations := []string{
+		"4d6kh3", "3d6!", "2d6!!", "2d6!p", "1d20ro1", "2d20kh1ro1",
+		"10d10>=8dbl10f1", "4d10!10>=8", "1d8r<2+1d6", "4df", "2d66",
+	}
+
+	for _, notation := range notations {
+		t.Run(notation, func(t *testing.T) {
+			dist, err := Analyze(notation)
+			if err != nil {
+				t.Fatalf("Analyze(%q) error: %v", notation, err)
+			}
+
+			roller := NewRoller(42)
+			total := 0.0
+			for range rolls {
+				result, err := roller.Roll(notation)
+				if err != nil {
+					t.Fatalf("Roll(%q) error: %v", notation, err)
+				}
+				total += float64(result.Value)
+			}
+
+			sampled := total / rolls
+			stdErr := dist.StdDev() / math.Sqrt(rolls)
+			if math.Abs(sampled-dist.Mean()) > 5*stdErr {
+				t.Errorf("sampled mean %v differs from analytic mean %v (std err %v)", sampled, dist.Mean(), stdErr)
+			}
+		})
+	}
+}
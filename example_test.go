@@ -119,6 +119,47 @@ func Example_advantageWithModifier() {
 	// Roll: 12
 }
 
+// Example_keepDrop shows dropping the lowest die of 4d6 and keeping the highest of 3d20.
+func Example_keepDrop() {
+	roller := d20.NewRoller(42)
+	result, _ := roller.Dice(4, 6).DropLowest(1).Roll()
+
+	fmt.Printf("Score: %d (kept: %v)\n", result.Value, result.Kept())
+	fmt.Println(result.Detail)
+
+	result, _ = roller.Roll("3d20kh1")
+	fmt.Printf("Elven Accuracy: %d (from %v)\n", result.Value, result.DiceRolls)
+	// Output:
+	// Score: 15 (kept: [6 6 3])
+	// Rolled 4d6dl1... 6, 6, 3, ~~1~~; *Result: 15*
+	// Elven Accuracy: 18 (from [4 6 18])
+}
+
+// Example_explodingDice shows dice that roll again on their maximum face.
+func Example_explodingDice() {
+	roller := d20.NewRoller(42)
+	result, _ := roller.Dice(3, 6).Explode().Roll()
+
+	fmt.Println(result.Detail)
+	// Output:
+	// Rolled 3d6!... 6!, 6!, 3, 1, 2; *Result: 18*
+}
+
+// Example_rerollOnce shows Great Weapon Fighting rerolling 1s and 2s on damage dice.
+func Example_rerollOnce() {
+	roller := d20.NewRoller(42)
+	for range 2 {
+		result, _ := roller.Dice(2, 6).
+			RerollOnce(d20.AtMost(2)).
+			WithModifier("strength", 3).
+			Roll()
+		fmt.Println(result.Detail)
+	}
+	// Output:
+	// Rolled 2d6ro<=2... 6, 6; +3 strength; *Result: 15*
+	// Rolled 2d6ro<=2... 3, 2 (rerolled 1); +3 strength; *Result: 8*
+}
+
 // Example_multipleDice shows rolling multiple dice.
 func Example_multipleDice() {
 	roller := d20.NewRoller(42)
@@ -452,12 +493,12 @@ func Example_rolledCharacterCreation() {
 
 	// Roll all 6 ability scores using 4d6 keep highest 3
 	attrs := map[string]string{
-		"strength":     "3d6",
-		"dexterity":    "3d6",
-		"constitution": "3d6",
-		"intelligence": "3d6",
-		"wisdom":       "3d6",
-		"charisma":     "3d6",
+		"strength":     "4d6kh3",
+		"dexterity":    "4d6kh3",
+		"constitution": "4d6kh3",
+		"intelligence": "4d6kh3",
+		"wisdom":       "4d6kh3",
+		"charisma":     "4d6kh3",
 	}
 
 	fighter, _ := d20.NewActor("Thorin").
@@ -475,8 +516,8 @@ func Example_rolledCharacterCreation() {
 	fmt.Printf("DEX: %d\n", dex)
 	// Output:
 	// HP: 82
-	// STR: 12
-	// DEX: 12
+	// STR: 11
+	// DEX: 14
 }
 
 // Example_mixedStaticAndRolled shows combining fixed values with rolled stats.
@@ -524,3 +565,72 @@ func Example_rolledCombatStats() {
 	// Output:
 	// Raging attack includes +2 rage: 10 total
 }
+
+// Example_dicePool shows a World of Darkness style success-counting pool.
+func Example_dicePool() {
+	roller := d20.NewRoller(42)
+	pool, _ := roller.Dice(6, 10).
+		CountSuccesses(8).
+		DoubleOn(d20.Equals(10)).
+		RollPool()
+
+	fmt.Printf("Successes: %d, Botch: %v\n", pool.Successes, pool.Botch)
+	fmt.Println(pool.Detail)
+	// Output:
+	// Successes: 2, Botch: false
+	// Rolled 6d10>=8dbl=10... 6, 8, 9, 1, 4, 6; *Result: 2*; 2 successes
+}
+
+// Example_fateDice shows Fate/Fudge dice, rendered as symbols in the detail.
+func Example_fateDice() {
+	roller := d20.NewRoller(42)
+	result, _ := roller.Roll("4dF+2")
+
+	fmt.Println(result.Value)
+	fmt.Println(result.Detail)
+	// Output:
+	// 4
+	// Rolled 4dF... [+], [+], [+], [-]; +2 modifier; *Result: 4*
+}
+
+// Example_criticalHit shows crit detection with an expanded crit range.
+func Example_criticalHit() {
+	roller := d20.NewRoller(30)
+	result, _ := roller.Dice(1, 20).CritRange(19).WithModifier("strength", 5).Roll()
+
+	fmt.Printf("Natural %d, critical: %v\n", result.Natural, result.IsCritical)
+	fmt.Println(result.Detail)
+	// Output:
+	// Natural 19, critical: true
+	// Rolled 1d20cs>=19... 19; +5 strength; *Result: 24*; *Critical!*
+}
+
+// Example_distribution shows exact odds computed without rolling.
+func Example_distribution() {
+	roller := d20.NewRoller(42)
+	dist, _ := roller.Dice(1, 20).WithAdvantage().WithModifier("attack", 5).Distribution()
+	fmt.Printf("Hit AC 15: %.1f%%\n", dist.ProbabilityAtLeast(15)*100)
+
+	stats, _ := d20.Analyze("4d6kh3")
+	fmt.Printf("4d6kh3: mean %.2f, range %d-%d\n", stats.Mean(), stats.Min(), stats.Max())
+	// Output:
+	// Hit AC 15: 79.8%
+	// 4d6kh3: mean 12.24, range 3-18
+}
+
+// Example_checkAgainstDC shows checking rolls against a DC with 5e natural-roll rules.
+func Example_checkAgainstDC() {
+	roller := d20.NewScriptedRoller(d20.NewScript(20, 20))
+	fighter, _ := d20.NewActor("fighter").WithHP(12).WithCombatModifier("strength", 3).Build()
+	fighter.SetAttribute("athletics", 3)
+
+	attack, _ := fighter.AttackRoll(roller).Against(25)
+	fmt.Printf("Attack vs AC 25: hit %v, margin %d\n", attack.Success, attack.Margin)
+
+	builder, _ := fighter.SkillCheck("athletics", roller)
+	check, _ := builder.Against(25)
+	fmt.Printf("Athletics vs DC 25: success %v, margin %d\n", check.Success, check.Margin)
+	// Output:
+	// Attack vs AC 25: hit true, margin -2
+	// Athletics vs DC 25: success false, margin -2
+}
@@ -0,0 +1,285 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errDivisionByZero = errors.New("division by zero in dice expression")
+
+// exprNode is a node in a parsed dice expression tree.
+type exprNode interface {
+	// eval computes the node's value, rolling any dice through the evaluation.
+	eval(ev *evaluation) (int, error)
+	// render formats the node with rolled dice values substituted for dice terms.
+	render(ev *evaluation) string
+	// String formats the node back to dice notation.
+	String() string
+	// distribution computes the exact probability distribution of the node's value.
+	distribution(an *analysis) (Distribution, error)
+}
+
+// evaluation carries the state of a single roll through an expression tree.
+type evaluation struct {
+	roller    *Roller
+	primary   *diceNode     // Dice term that advantage/disadvantage applies to
+	advantage AdvantageType // Advantage state for the primary term
+	dice      []DieResult   // Every die rolled, in evaluation order
+	results   map[*diceNode][]DieResult
+	terms     []TermResult // Each dice term rolled, in evaluation order
+	pool      *poolTally   // Success-counting totals; nil unless a term counts successes
+}
+
+func newEvaluation(roller *Roller, primary *diceNode, advantage AdvantageType) *evaluation {
+	return &evaluation{
+		roller:    roller,
+		primary:   primary,
+		advantage: advantage,
+		results:   make(map[*diceNode][]DieResult),
+	}
+}
+
+// numberNode is a constant integer.
+type numberNode struct {
+	value int
+}
+
+func (n *numberNode) eval(*evaluation) (int, error) { return n.value, nil }
+func (n *numberNode) render(*evaluation) string     { return n.String() }
+func (n *numberNode) String() string                { return fmt.Sprintf("%d", n.value) }
+
+// diceNode rolls a group of identical dice and sums the ones that are kept.
+type diceNode struct {
+	count   uint
+	faces   uint // Distinct faces: 3 for Fate dice and 36 for d66
+	kind    DieKind
+	keep    keepRule
+	explode explodeRule
+	reroll  rerollRule
+	pool    poolRule
+	crit    critRule
+}
+
+// validate checks that the dice term can be rolled.
+func (n *diceNode) validate() error {
+	if n.count == 0 {
+		return errRollCountZero
+	}
+	if n.faces == 0 {
+		return errDieFacesZero
+	}
+	if err := n.keep.validate(n.count); err != nil {
+		return err
+	}
+	return n.pool.validate()
+}
+
+func (n *diceNode) eval(ev *evaluation) (int, error) {
+	if err := n.validate(); err != nil {
+		return 0, err
+	}
+
+	advantage := Normal
+	if n == ev.primary {
+		advantage = ev.advantage
+	}
+
+	dice, total := ev.roller.rollDice(n, advantage)
+	if n.pool.counts() {
+		total = ev.countSuccesses(n.pool, dice)
+	}
+	for i := range dice {
+		dice[i].Term = len(ev.terms)
+	}
+	ev.terms = append(ev.terms, TermResult{Notation: n.String(), Value: total})
+	ev.dice = append(ev.dice, dice...)
+	ev.results[n] = dice
+	return total, nil
+}
+
+func (n *diceNode) render(ev *evaluation) string {
+	return "[" + formatDice(ev.results[n]) + "]"
+}
+
+func (n *diceNode) String() string {
+	die := fmt.Sprintf("d%d", n.faces)
+	switch n.kind {
+	case FateDie:
+		die = "dF"
+	case D66Die:
+		die = "d66"
+	}
+	// The pool target comes before the explode rule, since a comparison
+	// written right after "!" would parse as the explosion's condition
+	return fmt.Sprintf("%d%s%s%s%s%s%s", n.count, die, n.reroll, n.pool, n.explode, n.keep, n.crit)
+}
+
+// maxFace returns the highest value a single die of the term can show.
+func (n *diceNode) maxFace() int {
+	switch n.kind {
+	case FateDie:
+		return 1
+	case D66Die:
+		return 66
+	}
+	return int(n.faces)
+}
+
+// newFateDice creates a term of Fate dice.
+func newFateDice(count uint) *diceNode {
+	return &diceNode{count: count, faces: 3, kind: FateDie}
+}
+
+// newD66Dice creates a term of d66 dice.
+func newD66Dice(count uint) *diceNode {
+	return &diceNode{count: count, faces: 36, kind: D66Die}
+}
+
+// binaryNode applies an arithmetic operator to two operands.
+// Division rounds down, as is conventional for tabletop games.
+type binaryNode struct {
+	op    byte // One of '+', '-', '*', '/'
+	left  exprNode
+	right exprNode
+}
+
+func (n *binaryNode) eval(ev *evaluation) (int, error) {
+	left, err := n.left.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(ev)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, errDivisionByZero
+		}
+		return floorDiv(left, right), nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func (n *binaryNode) render(ev *evaluation) string {
+	return n.left.render(ev) + " " + string(n.op) + " " + n.right.render(ev)
+}
+
+func (n *binaryNode) String() string {
+	return n.left.String() + string(n.op) + n.right.String()
+}
+
+// negateNode negates its operand.
+type negateNode struct {
+	operand exprNode
+}
+
+func (n *negateNode) eval(ev *evaluation) (int, error) {
+	value, err := n.operand.eval(ev)
+	return -value, err
+}
+
+func (n *negateNode) render(ev *evaluation) string { return "-" + n.operand.render(ev) }
+func (n *negateNode) String() string               { return "-" + n.operand.String() }
+
+// groupNode is a parenthesized sub-expression.
+type groupNode struct {
+	inner exprNode
+}
+
+func (n *groupNode) eval(ev *evaluation) (int, error) { return n.inner.eval(ev) }
+func (n *groupNode) render(ev *evaluation) string     { return "(" + n.inner.render(ev) + ")" }
+func (n *groupNode) String() string                   { return "(" + n.inner.String() + ")" }
+
+// floorDiv divides a by b, rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// walkDice calls fn for every dice term in the expression, left to right.
+func walkDice(node exprNode, fn func(*diceNode)) {
+	switch n := node.(type) {
+	case *diceNode:
+		fn(n)
+	case *binaryNode:
+		walkDice(n.left, fn)
+		walkDice(n.right, fn)
+	case *negateNode:
+		walkDice(n.operand, fn)
+	case *groupNode:
+		walkDice(n.inner, fn)
+	}
+}
+
+// firstDice returns the leftmost dice term in the expression, or nil if there is none.
+func firstDice(node exprNode) *diceNode {
+	var first *diceNode
+	walkDice(node, func(n *diceNode) {
+		if first == nil {
+			first = n
+		}
+	})
+	return first
+}
+
+// splitModifiers separates constant terms at the top level of an additive
+// expression into named modifiers, so "2d6+1d4+3" becomes the dice expression
+// "2d6+1d4" with a +3 modifier. The returned expression always contains dice
+// if the input did.
+func splitModifiers(node exprNode) (exprNode, []Modifier) {
+	type signedTerm struct {
+		negative bool
+		node     exprNode
+	}
+
+	// Flatten the left-associative chain of + and - operators.
+	var terms []signedTerm
+	var flatten func(n exprNode, negative bool)
+	flatten = func(n exprNode, negative bool) {
+		if bin, ok := n.(*binaryNode); ok && (bin.op == '+' || bin.op == '-') {
+			flatten(bin.left, negative)
+			flatten(bin.right, negative != (bin.op == '-'))
+			return
+		}
+		terms = append(terms, signedTerm{negative: negative, node: n})
+	}
+	flatten(node, false)
+
+	var modifiers []Modifier
+	var dice exprNode
+	for _, term := range terms {
+		if num, ok := term.node.(*numberNode); ok {
+			value := num.value
+			if term.negative {
+				value = -value
+			}
+			modifiers = append(modifiers, NewModifier("modifier", value))
+			continue
+		}
+
+		switch {
+		case dice == nil && term.negative:
+			dice = &negateNode{operand: term.node}
+		case dice == nil:
+			dice = term.node
+		case term.negative:
+			dice = &binaryNode{op: '-', left: dice, right: term.node}
+		default:
+			dice = &binaryNode{op: '+', left: dice, right: term.node}
+		}
+	}
+
+	return dice, modifiers
+}
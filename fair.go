@@ -0,0 +1,222 @@
+package d20
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+var (
+	errFairSessionRevealed = errors.New("fair session seed has already been revealed")
+	errFairCommitment      = errors.New("server seed does not match commitment")
+	errFairRollMismatch    = errors.New("fair roll does not match its replay")
+	errFairNonceSequence   = errors.New("fair rolls do not cover every nonce")
+)
+
+// fairSeedSize is the number of random bytes in a fair session's server seed.
+const fairSeedSize = 32
+
+// FairSession rolls dice provably fairly using commit-reveal.
+//
+// The session publishes Commitment, a SHA-256 hash of a secret server seed,
+// before any dice are rolled. Each roll mixes the server seed with a
+// client-supplied seed and an incrementing nonce, so neither side alone
+// controls the result. Reveal publishes the server seed once play is over,
+// and anyone can then check every recorded roll with VerifyFairRolls.
+// A FairSession is safe for concurrent use.
+type FairSession struct {
+	mu       sync.Mutex
+	seed     []byte
+	nonce    uint64
+	revealed bool
+}
+
+// FairRoll is a roll made by a FairSession, with everything needed to
+// verify it once the server seed is revealed.
+type FairRoll struct {
+	Nonce      uint64      // Position of the roll in the session, starting at 1
+	ClientSeed string      // Seed supplied by the player
+	Notation   string      // Dice notation that was rolled
+	Outcome    RollOutcome // Result of the roll
+}
+
+// NewFairSession creates a FairSession with a server seed from crypto/rand.
+//
+// Example:
+//
+//	session := d20.NewFairSession()
+//	publish(session.Commitment())
+//	roll, _ := session.Roll("player-chosen-seed", "1d20+5")
+//	// ... at the end of the session
+//	publish(session.Reveal())
+func NewFairSession() *FairSession {
+	seed := make([]byte, fairSeedSize)
+	_, _ = crand.Read(seed) // crypto/rand.Read never returns an error
+	return &FairSession{seed: seed}
+}
+
+// NewFairSessionWithSeed creates a FairSession with a known server seed,
+// such as one generated and stored elsewhere. Its first roll uses nonce 1;
+// use ResumeFairSession to continue a session that has already rolled.
+func NewFairSessionWithSeed(seed []byte) *FairSession {
+	return ResumeFairSession(seed, 0)
+}
+
+// ResumeFairSession continues a session whose commitment is already
+// published, such as after a server restart. lastNonce is the nonce of the
+// session's last roll, so the next roll uses lastNonce+1 and no nonce is
+// repeated.
+//
+// Example:
+//
+//	session := d20.ResumeFairSession(savedSeed, rolls[len(rolls)-1].Nonce)
+func ResumeFairSession(seed []byte, lastNonce uint64) *FairSession {
+	return &FairSession{seed: slices.Clone(seed), nonce: lastNonce}
+}
+
+// Commitment returns the hex-encoded SHA-256 hash of the server seed.
+// Publish it before rolling so the seed can't be changed afterward.
+func (s *FairSession) Commitment() string {
+	return fairCommitment(s.seed)
+}
+
+// Roll rolls dice notation using the server seed, the client seed and the
+// next nonce. Returns an error if the notation is invalid or the server seed
+// has been revealed, since later rolls would be predictable.
+func (s *FairSession) Roll(clientSeed, notation string) (FairRoll, error) {
+	s.mu.Lock()
+	if s.revealed {
+		s.mu.Unlock()
+		return FairRoll{}, errFairSessionRevealed
+	}
+	s.nonce++
+	nonce := s.nonce
+	s.mu.Unlock()
+
+	outcome, err := rollFair(s.seed, clientSeed, nonce, notation)
+	if err != nil {
+		return FairRoll{}, err
+	}
+	return FairRoll{Nonce: nonce, ClientSeed: clientSeed, Notation: notation, Outcome: outcome}, nil
+}
+
+// Reveal ends the session and returns the hex-encoded server seed, so
+// players can verify every roll. The session can't roll afterward.
+func (s *FairSession) Reveal() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revealed = true
+	return hex.EncodeToString(s.seed)
+}
+
+// VerifyFairRolls checks a revealed fair session. It confirms that the
+// hex-encoded server seed matches the published commitment and that the rolls
+// use each nonce from 1 to len(rolls) exactly once, so no roll was made and
+// hidden. It then replays every roll and compares its dice, value and detail
+// with the recorded outcome. Returns nil if every roll is genuine.
+//
+// Rolls made after the last one recorded can't be detected here, so players
+// should also check that the count matches the rolls they saw.
+//
+// Example:
+//
+//	err := d20.VerifyFairRolls(commitment, revealedSeed, rolls)
+func VerifyFairRolls(commitment, serverSeed string, rolls []FairRoll) error {
+	seed, err := hex.DecodeString(serverSeed)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errFairCommitment, err)
+	}
+	if !hmac.Equal([]byte(fairCommitment(seed)), []byte(commitment)) {
+		return errFairCommitment
+	}
+	if err := checkFairNonces(rolls); err != nil {
+		return err
+	}
+
+	for _, roll := range rolls {
+		replay, err := rollFair(seed, roll.ClientSeed, roll.Nonce, roll.Notation)
+		if err != nil {
+			return fmt.Errorf("%w: nonce %d: %v", errFairRollMismatch, roll.Nonce, err)
+		}
+		if replay.Value != roll.Outcome.Value ||
+			!slices.Equal(replay.DiceRolls, roll.Outcome.DiceRolls) ||
+			replay.Detail != roll.Outcome.Detail {
+			return fmt.Errorf("%w: nonce %d rolled %q, recorded %q", errFairRollMismatch, roll.Nonce, replay.Detail, roll.Outcome.Detail)
+		}
+	}
+	return nil
+}
+
+// checkFairNonces confirms that the rolls use each nonce from 1 to
+// len(rolls) exactly once, in any order.
+func checkFairNonces(rolls []FairRoll) error {
+	nonces := make([]uint64, len(rolls))
+	for i, roll := range rolls {
+		nonces[i] = roll.Nonce
+	}
+	slices.Sort(nonces)
+	for i, nonce := range nonces {
+		want := uint64(i + 1)
+		switch {
+		case nonce < want:
+			return fmt.Errorf("%w: nonce %d is repeated", errFairNonceSequence, nonce)
+		case nonce > want:
+			return fmt.Errorf("%w: nonce %d is missing", errFairNonceSequence, want)
+		}
+	}
+	return nil
+}
+
+// fairCommitment returns the hex-encoded SHA-256 hash of a server seed.
+func fairCommitment(seed []byte) string {
+	sum := sha256.Sum256(seed)
+	return hex.EncodeToString(sum[:])
+}
+
+// rollFair rolls notation with the random stream for one nonce.
+func rollFair(seed []byte, clientSeed string, nonce uint64, notation string) (RollOutcome, error) {
+	source := &fairSource{seed: seed, clientSeed: clientSeed, nonce: nonce}
+	return NewRollerWithSource(source).Roll(notation)
+}
+
+// fairSource is the random stream for a single fair roll. Blocks of bytes
+// are HMAC-SHA256(server seed, "clientSeed:nonce:block"), read eight bytes at
+// a time.
+type fairSource struct {
+	seed       []byte
+	clientSeed string
+	nonce      uint64
+	block      uint64
+	buf        []byte
+}
+
+func (s *fairSource) IntN(n int) int {
+	// Reject values past the largest multiple of n to avoid modulo bias
+	limit := math.MaxUint64 - math.MaxUint64%uint64(n)
+	for {
+		if v := s.uint64(); v < limit {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// uint64 returns the next eight bytes of the stream.
+func (s *fairSource) uint64() uint64 {
+	if len(s.buf) < 8 {
+		mac := hmac.New(sha256.New, s.seed)
+		mac.Write([]byte(s.clientSeed + ":" + strconv.FormatUint(s.nonce, 10) + ":" + strconv.FormatUint(s.block, 10)))
+		s.buf = mac.Sum(nil)
+		s.block++
+	}
+	v := binary.BigEndian.Uint64(s.buf)
+	s.buf = s.buf[8:]
+	return v
+}
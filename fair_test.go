@@ -0,0 +1,171 @@
+package d20
+
+import (
+	"encoding/hex"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFairSession_Verify(t *testing.T) {
+	session := NewFairSession()
+	commitment := session.Commitment()
+
+	var rolls []FairRoll
+	for _, notation := range []string{"1d20+5", "4d6kh3", "2d6!+1d4", "10d10>=8"} {
+		roll, err := session.Roll("player-seed", notation)
+		if err != nil {
+			t.Fatalf("Roll(%q) error: %v", notation, err)
+		}
+		rolls = append(rolls, roll)
+	}
+	if rolls[0].Nonce != 1 || rolls[3].Nonce != 4 {
+		t.Errorf("nonces = %d..%d, want 1..4", rolls[0].Nonce, rolls[3].Nonce)
+	}
+
+	seed := session.Reveal()
+	if _, err := session.Roll("player-seed", "1d20"); !errors.Is(err, errFairSessionRevealed) {
+		t.Errorf("rolling after reveal: error = %v, want %v", err, errFairSessionRevealed)
+	}
+
+	if err := VerifyFairRolls(commitment, seed, rolls); err != nil {
+		t.Fatalf("VerifyFairRolls() error: %v", err)
+	}
+
+	t.Run("Wrong seed", func(t *testing.T) {
+		other := NewFairSession().Reveal()
+		if err := VerifyFairRolls(commitment, other, rolls); !errors.Is(err, errFairCommitment) {
+			t.Errorf("error = %v, want %v", err, errFairCommitment)
+		}
+		if err := VerifyFairRolls(commitment, "not hex", rolls); !errors.Is(err, errFairCommitment) {
+			t.Errorf("error = %v, want %v", err, errFairCommitment)
+		}
+	})
+
+	t.Run("Fudged value", func(t *testing.T) {
+		fudged := slices.Clone(rolls)
+		fudged[0].Outcome.Value++
+		if err := VerifyFairRolls(commitment, seed, fudged); !errors.Is(err, errFairRollMismatch) {
+			t.Errorf("error = %v, want %v", err, errFairRollMismatch)
+		}
+	})
+
+	t.Run("Repeated nonce", func(t *testing.T) {
+		// A server that rolls twice and keeps the better roll repeats a nonce...
+		reroll, err := NewFairSessionWithSeed(mustDecodeHex(t, seed)).Roll("player-seed", "1d20+5")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		repeated := append(slices.Clone(rolls), reroll)
+		if err := VerifyFairRolls(commitment, seed, repeated); !errors.Is(err, errFairNonceSequence) {
+			t.Errorf("error = %v, want %v", err, errFairNonceSequence)
+		}
+	})
+
+	t.Run("Missing nonce", func(t *testing.T) {
+		// ...or hides the roll it didn't like, leaving a gap
+		missing := slices.Delete(slices.Clone(rolls), 1, 2)
+		if err := VerifyFairRolls(commitment, seed, missing); !errors.Is(err, errFairNonceSequence) {
+			t.Errorf("error = %v, want %v", err, errFairNonceSequence)
+		}
+	})
+
+	t.Run("Any order", func(t *testing.T) {
+		reversed := slices.Clone(rolls)
+		slices.Reverse(reversed)
+		if err := VerifyFairRolls(commitment, seed, reversed); err != nil {
+			t.Errorf("VerifyFairRolls() error: %v", err)
+		}
+	})
+
+	t.Run("Changed client seed", func(t *testing.T) {
+		changed := slices.Clone(rolls)
+		changed[1].ClientSeed = "server-chosen-seed"
+		if err := VerifyFairRolls(commitment, seed, changed); !errors.Is(err, errFairRollMismatch) {
+			t.Errorf("error = %v, want %v", err, errFairRollMismatch)
+		}
+	})
+}
+
+func TestFairSession_Deterministic(t *testing.T) {
+	seed := []byte("a fixed server seed for testing!")
+	a := NewFairSessionWithSeed(seed)
+	b := NewFairSessionWithSeed(seed)
+	if a.Commitment() != b.Commitment() {
+		t.Fatal("sessions with the same seed should share a commitment")
+	}
+
+	for range 20 {
+		rollA, err := a.Roll("client", "3d6")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		rollB, err := b.Roll("client", "3d6")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if !slices.Equal(rollA.Outcome.DiceRolls, rollB.Outcome.DiceRolls) {
+			t.Fatalf("same seed, client seed and nonce rolled %v and %v", rollA.Outcome.DiceRolls, rollB.Outcome.DiceRolls)
+		}
+	}
+
+	// A different client seed gives different dice for the same nonce
+	rollA, err := NewFairSessionWithSeed(seed).Roll("client", "10d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	rollC, err := NewFairSessionWithSeed(seed).Roll("other", "10d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if slices.Equal(rollA.Outcome.DiceRolls, rollC.Outcome.DiceRolls) {
+		t.Error("client seed should change the dice")
+	}
+}
+
+func TestFairSession_Resume(t *testing.T) {
+	seed := []byte("a fixed server seed for testing!")
+	original := NewFairSessionWithSeed(seed)
+	var rolls []FairRoll
+	for range 3 {
+		roll, err := original.Roll("client", "1d20")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		rolls = append(rolls, roll)
+	}
+
+	resumed := ResumeFairSession(seed, rolls[len(rolls)-1].Nonce)
+	if resumed.Commitment() != original.Commitment() {
+		t.Fatal("a resumed session should keep its commitment")
+	}
+	next, err := resumed.Roll("client", "1d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if next.Nonce != 4 {
+		t.Errorf("resumed Nonce = %d, want 4", next.Nonce)
+	}
+
+	want, err := original.Roll("client", "1d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if !slices.Equal(next.Outcome.DiceRolls, want.Outcome.DiceRolls) {
+		t.Errorf("resumed session rolled %v, want %v", next.Outcome.DiceRolls, want.Outcome.DiceRolls)
+	}
+
+	rolls = append(rolls, next)
+	if err := VerifyFairRolls(resumed.Commitment(), resumed.Reveal(), rolls); err != nil {
+		t.Errorf("VerifyFairRolls() error: %v", err)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("DecodeString() error: %v", err)
+	}
+	return b
+}
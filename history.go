@@ -0,0 +1,168 @@
+package d20
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// History is an audit log of rolls, recorded by every Roller it's attached
+// to with Roller.WithHistory. A History with a capacity keeps only the most
+// recent rolls, discarding the oldest like a ring buffer.
+// A History is safe for concurrent use.
+type History struct {
+	mu       sync.Mutex
+	entries  []HistoryEntry // Ring buffer once full; start is the oldest entry
+	start    int
+	capacity int              // Maximum entries kept; zero keeps every entry
+	now      func() time.Time // Clock used to timestamp entries
+}
+
+// HistoryEntry records a single roll.
+type HistoryEntry struct {
+	Time      time.Time   `json:"time"`
+	Notation  string      `json:"notation"`           // What was rolled, e.g. "1d20 with advantage"
+	Dice      []DieResult `json:"dice"`               // Each die rolled
+	Modifiers []Modifier  `json:"modifiers"`          // Applied modifiers, including typed and dice modifiers
+	Value     int         `json:"value"`              // Final result
+	Detail    string      `json:"detail"`             // Formatted roll description
+	ActorID   string      `json:"actor_id,omitempty"` // Actor that rolled, if known
+	Label     string      `json:"label,omitempty"`    // Caller-supplied label, e.g. "initiative"
+}
+
+// NewHistory creates an empty History. A positive capacity keeps only the most
+// recent rolls; zero or less keeps every roll.
+//
+// Example:
+//
+//	history := d20.NewHistory(1000)
+//	roller := d20.NewRoller(42).WithHistory(history)
+func NewHistory(capacity int) *History {
+	return &History{capacity: max(capacity, 0), now: time.Now}
+}
+
+// WithHistory records every roll made by the Roller, including
+// D100SkillCheck, in history. Pass nil to stop recording.
+// Returns the Roller for chaining.
+func (r *Roller) WithHistory(history *History) *Roller {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = history
+	return r
+}
+
+// ForActor records the roll in the Roller's history as made by the actor with
+// the given ID. Actor roll methods such as SkillCheck and AttackRoll set this
+// automatically.
+func (rb *RollBuilder) ForActor(id string) *RollBuilder {
+	rb.actorID = normalizeID(id)
+	return rb
+}
+
+// Label records the roll in the Roller's history under a label such as
+// "initiative" or "fireball damage".
+func (rb *RollBuilder) Label(label string) *RollBuilder {
+	rb.label = label
+	return rb
+}
+
+// newHistoryEntry creates an entry for a roll outcome. The entry keeps its
+// own copy of the dice and modifiers, so changes to the outcome returned to
+// the caller don't alter the log.
+func newHistoryEntry(notation string, outcome RollOutcome, actorID, label string) HistoryEntry {
+	entry := HistoryEntry{
+		Notation:  notation,
+		Dice:      outcome.Dice,
+		Modifiers: outcome.Modifiers,
+		Value:     outcome.Value,
+		Detail:    outcome.Detail,
+		ActorID:   actorID,
+		Label:     label,
+	}
+	return entry.clone()
+}
+
+// clone returns a copy of the entry that shares no slices with it.
+func (e HistoryEntry) clone() HistoryEntry {
+	e.Dice = slices.Clone(e.Dice)
+	for i := range e.Dice {
+		e.Dice[i].Rerolled = slices.Clone(e.Dice[i].Rerolled)
+	}
+	e.Modifiers = slices.Clone(e.Modifiers)
+	return e
+}
+
+// record adds an entry for a roll, stamping it with the current time.
+func (h *History) record(entry HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry.Time = h.now()
+	if h.capacity == 0 || len(h.entries) < h.capacity {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.start] = entry
+	h.start = (h.start + 1) % h.capacity
+}
+
+// Entries returns every recorded roll, oldest first.
+func (h *History) Entries() []HistoryEntry {
+	return h.filter(func(HistoryEntry) bool { return true })
+}
+
+// ForActor returns the rolls made by the actor with the given ID, oldest first.
+func (h *History) ForActor(id string) []HistoryEntry {
+	id = normalizeID(id)
+	return h.filter(func(e HistoryEntry) bool { return e.ActorID == id })
+}
+
+// Between returns the rolls made at or after from and before to, oldest first.
+func (h *History) Between(from, to time.Time) []HistoryEntry {
+	return h.filter(func(e HistoryEntry) bool {
+		return !e.Time.Before(from) && e.Time.Before(to)
+	})
+}
+
+// Len returns the number of recorded rolls.
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.entries)
+}
+
+// Clear removes every recorded roll.
+func (h *History) Clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries, h.start = nil, 0
+}
+
+// WriteJSONL writes every recorded roll to w as JSON Lines, one entry per
+// line, oldest first.
+func (h *History) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range h.Entries() {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter returns copies of the entries matching keep, oldest first.
+func (h *History) filter(keep func(HistoryEntry) bool) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var matched []HistoryEntry
+	for i := range h.entries {
+		entry := h.entries[(h.start+i)%len(h.entries)]
+		if keep(entry) {
+			matched = append(matched, entry.clone())
+		}
+	}
+	return matched
+}
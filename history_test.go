@@ -0,0 +1,220 @@
+package d20
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestHistory returns a History whose clock advances one minute per roll
+// from a fixed start time.
+func newTestHistory(capacity int) (*History, time.Time) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	history := NewHistory(capacity)
+	next := start
+	history.now = func() time.Time {
+		now := next
+		next = next.Add(time.Minute)
+		return now
+	}
+	return history, start
+}
+
+func TestHistory_Records(t *testing.T) {
+	history, start := newTestHistory(0)
+	roller := NewRoller(42).WithHistory(history)
+	fighter, err := NewActor("Fighter 1").WithHP(20).WithAttribute("athletics", 5).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if _, err := roller.Roll("2d6+3"); err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	attack, err := fighter.AttackRoll(roller).WithAdvantage().Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	check, err := fighter.SkillCheck("athletics", roller)
+	if err != nil {
+		t.Fatalf("SkillCheck() error: %v", err)
+	}
+	if _, err := check.Roll(); err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if _, err := roller.Dice(1, 20).Label("initiative").ForActor("Goblin").Roll(); err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+
+	entries := history.Entries()
+	if len(entries) != 4 || history.Len() != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Notation != "2d6" || len(first.Dice) != 2 || len(first.Modifiers) != 1 || first.Modifiers[0].Value != 3 {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if !first.Time.Equal(start) {
+		t.Errorf("first entry time = %v, want %v", first.Time, start)
+	}
+
+	second := entries[1]
+	if second.Notation != "1d20 with advantage" || second.ActorID != "fighter_1" || second.Label != "attack" {
+		t.Errorf("unexpected attack entry: %+v", second)
+	}
+	if second.Value != attack.Value || len(second.Dice) != 2 {
+		t.Errorf("attack entry should match the outcome: %+v", second)
+	}
+
+	if got := history.ForActor("Fighter 1"); len(got) != 2 || got[1].Label != "athletics" {
+		t.Errorf("ForActor(fighter) = %+v", got)
+	}
+	if got := history.ForActor("goblin"); len(got) != 1 || got[0].Label != "initiative" {
+		t.Errorf("ForActor(goblin) = %+v", got)
+	}
+	if got := history.Between(start.Add(time.Minute), start.Add(3*time.Minute)); len(got) != 2 {
+		t.Errorf("Between() returned %d entries, want 2", len(got))
+	}
+}
+
+func TestHistory_RingBuffer(t *testing.T) {
+	history, _ := newTestHistory(3)
+	roller := NewRoller(42).WithHistory(history)
+
+	for i := range 5 {
+		if _, err := roller.Roll("1d20"); err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if history.Len() != min(i+1, 3) {
+			t.Fatalf("Len() = %d after %d rolls", history.Len(), i+1)
+		}
+	}
+
+	entries := history.Entries()
+	for i := 1; i < len(entries); i++ {
+		if !entries[i].Time.After(entries[i-1].Time) {
+			t.Fatalf("entries out of order: %v then %v", entries[i-1].Time, entries[i].Time)
+		}
+	}
+	// The two oldest rolls were discarded
+	if want := time.Date(2024, 1, 1, 20, 2, 0, 0, time.UTC); !entries[0].Time.Equal(want) {
+		t.Errorf("oldest entry time = %v, want %v", entries[0].Time, want)
+	}
+
+	history.Clear()
+	if history.Len() != 0 {
+		t.Errorf("Len() = %d after Clear()", history.Len())
+	}
+}
+
+func TestHistory_D100AndPools(t *testing.T) {
+	history, _ := newTestHistory(0)
+	roller := NewRoller(42).WithHistory(history)
+	investigator, err := NewActor("investigator").WithHP(10).WithAttribute("stealth", 45).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if _, _, err := investigator.D100SkillCheck("stealth", roller, 0); err != nil {
+		t.Fatalf("D100SkillCheck() error: %v", err)
+	}
+	if _, err := roller.RollPool("5d10>=8"); err != nil {
+		t.Fatalf("RollPool() error: %v", err)
+	}
+
+	entries := history.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Notation != "1d100" || entries[0].ActorID != "investigator" || entries[0].Label != "stealth" {
+		t.Errorf("unexpected D100 entry: %+v", entries[0])
+	}
+	if entries[1].Notation != "5d10>=8" {
+		t.Errorf("unexpected pool entry: %+v", entries[1])
+	}
+
+	roller.WithHistory(nil)
+	if _, err := roller.Roll("1d20"); err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if history.Len() != 2 {
+		t.Errorf("rolls should not be recorded after detaching the history")
+	}
+}
+
+func TestHistory_WriteJSONL(t *testing.T) {
+	history, _ := newTestHistory(0)
+	roller := NewRoller(42).WithHistory(history)
+	for range 3 {
+		if _, err := roller.Dice(1, 20).WithModifier("strength", 2).ForActor("hero").Roll(); err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := history.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL() error: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+		if entry.ActorID != "hero" || entry.Value != entry.Dice[0].Value+2 {
+			t.Errorf("unexpected entry: %+v", entry)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("expected 3 lines, got %d", lines)
+	}
+}
+
+func TestHistory_KeepsOwnCopy(t *testing.T) {
+	history, _ := newTestHistory(0)
+	roller := NewScriptedRoller(NewScript(1, 2)).WithHistory(history)
+
+	outcome, err := roller.Dice(1, 6).RerollOnce(Equals(1)).WithModifier("strength", 3).Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	outcome.Dice[0].Value = 20
+	outcome.Dice[0].Rerolled[0] = 6
+	outcome.Modifiers[0].Value = 10
+
+	entries := history.Entries()
+	entries[0].Dice[0].Raw = 20
+
+	entry := history.Entries()[0]
+	if entry.Dice[0].Value != 2 || entry.Dice[0].Raw != 2 || entry.Dice[0].Rerolled[0] != 1 || entry.Modifiers[0].Value != 3 {
+		t.Errorf("history changed with the outcome: %+v", entry)
+	}
+}
+
+func TestHistory_JSONShape(t *testing.T) {
+	history, _ := newTestHistory(0)
+	roller := NewScriptedRoller(NewScript(4)).WithHistory(history)
+	if _, err := roller.Dice(1, 6).WithModifier("strength", 2).Roll(); err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := history.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL() error: %v", err)
+	}
+	for _, want := range []string{
+		`"dice":[{"faces":6,"value":4,"raw":4,"term":0}]`,
+		`"modifiers":[{"value":2,"reason":"strength"}]`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("JSONL %s does not contain %s", buf.String(), want)
+		}
+	}
+}
@@ -1,13 +1,23 @@
 package d20
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // Modifier is a bonus or penalty applied to a dice roll.
 // Fields are public for flexibility, but it's recommended to use lowercase
 // for the Reason field to maintain consistency in formatted output.
+//
+// A dice modifier, such as Bless adding 1d4, sets Dice to the notation to roll.
+// Its Value is filled in with the amount rolled each time the modifier is
+// applied, so the modifiers in a RollOutcome show what each one added.
 type Modifier struct {
-	Value  int    // Positive for bonus, negative for penalty
-	Reason string // Description of the modifier source (e.g., "strength", "proficiency")
+	Value  int          `json:"value"`          // Positive for bonus, negative for penalty; the amount rolled for a dice modifier
+	Reason string       `json:"reason"`         // Description of the modifier source (e.g., "strength", "proficiency")
+	Dice   string       `json:"dice,omitempty"` // Dice notation rolled for the value (e.g., "1d4" for bless); empty for a flat modifier
+	Type   ModifierType `json:"type,omitempty"` // Bonus type for stacking rules (e.g., Status); Untyped by default
 }
 
 // NewModifier creates a new Modifier with the reason automatically lowercased
@@ -18,3 +28,84 @@ func NewModifier(reason string, value int) Modifier {
 		Reason: strings.ToLower(reason),
 	}
 }
+
+// NewTypedModifier creates a Modifier with a bonus type, such as a +1 status
+// bonus, for systems where modifiers of the same type don't stack.
+// The reason is automatically lowercased.
+//
+// Example:
+//
+//	heroism := d20.NewTypedModifier("heroism", d20.Status, 1)
+func NewTypedModifier(reason string, kind ModifierType, value int) Modifier {
+	return NewModifier(reason, value).OfType(kind)
+}
+
+// NewDiceModifier creates a Modifier that rolls dice notation for its value
+// each time it is applied, such as "1d4" for Bless or "-1d4" for Bane.
+// The reason and notation are automatically lowercased, as in Roller.Notation.
+// Returns an error if the notation is invalid.
+//
+// Example:
+//
+//	bless, _ := d20.NewDiceModifier("bless", "1d4")
+func NewDiceModifier(reason string, notation string) (Modifier, error) {
+	expr, err := parseNotation(normalizeNotation(notation))
+	if err != nil {
+		return Modifier{}, err
+	}
+	return Modifier{
+		Reason: strings.ToLower(reason),
+		// Stored lowercase so it parses again when rolled, e.g. "1df"
+		Dice: strings.ToLower(expr.String()),
+	}, nil
+}
+
+// OfType returns a copy of the modifier with the given bonus type,
+// e.g. to make a dice modifier a status bonus.
+func (m Modifier) OfType(kind ModifierType) Modifier {
+	m.Type = ModifierType(strings.ToLower(string(kind)))
+	return m
+}
+
+// IsDice reports whether the modifier rolls dice for its value.
+func (m Modifier) IsDice() bool {
+	return m.Dice != ""
+}
+
+// String formats the modifier as shown in a roll's detail, e.g. "+3 strength",
+// "+1d4 (3) bless" or "+1 heroism (status)".
+func (m Modifier) String() string {
+	s := fmt.Sprintf("%+d %s", m.Value, strings.ToLower(m.Reason))
+	if m.IsDice() {
+		sign := "+"
+		if strings.HasPrefix(m.Dice, "-") {
+			sign = ""
+		}
+		s = fmt.Sprintf("%s%s (%d) %s", sign, m.Dice, m.Value, strings.ToLower(m.Reason))
+	}
+	if m.Type != Untyped {
+		s += " (" + string(m.Type) + ")"
+	}
+	return s
+}
+
+// rollModifiers returns a copy of the modifiers with each dice modifier's
+// Value set to a fresh roll. The caller must hold the roller's lock.
+func (r *Roller) rollModifiers(modifiers []Modifier) ([]Modifier, error) {
+	rolled := slices.Clone(modifiers)
+	for i, mod := range rolled {
+		if !mod.IsDice() {
+			continue
+		}
+		expr, err := parseNotation(mod.Dice)
+		if err != nil {
+			return nil, err
+		}
+		value, err := expr.eval(newEvaluation(r, nil, Normal))
+		if err != nil {
+			return nil, err
+		}
+		rolled[i].Value = value
+	}
+	return rolled, nil
+}
@@ -0,0 +1,482 @@
+package d20
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxNotationDice caps the number of dice a single notation term may roll,
+// protecting callers that accept notation from untrusted input.
+const maxNotationDice = 1000
+
+// tokenKind identifies the lexical category of a notation token.
+type tokenKind int
+
+const (
+	tokenEOF     tokenKind = iota
+	tokenNumber            // Unsigned integer literal, e.g. "20"
+	tokenWord              // Run of letters, e.g. "d"
+	tokenPlus              // "+"
+	tokenMinus             // "-"
+	tokenStar              // "*"
+	tokenSlash             // "/"
+	tokenLParen            // "("
+	tokenRParen            // ")"
+	tokenBang              // "!"
+	tokenCompare           // One of "=", ">=", "<=", ">", "<"
+	tokenPercent           // "%"
+)
+
+// token is a single lexical element of a dice notation string.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // Zero-based character offset of the token in the notation string
+}
+
+// String returns a readable form of the token for error messages.
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of notation"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+// tokenize splits a lowercased dice notation string into tokens.
+// Whitespace between tokens is ignored.
+func tokenize(notation string) ([]token, error) {
+	var tokens []token
+	runes := []rune(notation)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c >= '0' && c <= '9':
+			for i < len(runes) && runes[i] >= '0' && runes[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+			continue
+		case c >= 'a' && c <= 'z':
+			for i < len(runes) && runes[i] >= 'a' && runes[i] <= 'z' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+			continue
+		}
+
+		// Two-character comparison operators
+		if (c == '>' || c == '<') && i+1 < len(runes) && runes[i+1] == '=' {
+			tokens = append(tokens, token{kind: tokenCompare, text: string(runes[i : i+2]), pos: start})
+			i += 2
+			continue
+		}
+
+		var kind tokenKind
+		switch c {
+		case '+':
+			kind = tokenPlus
+		case '-':
+			kind = tokenMinus
+		case '*':
+			kind = tokenStar
+		case '/':
+			kind = tokenSlash
+		case '(':
+			kind = tokenLParen
+		case ')':
+			kind = tokenRParen
+		case '!':
+			kind = tokenBang
+		case '=', '>', '<':
+			kind = tokenCompare
+		case '%':
+			kind = tokenPercent
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q at position %d", errInvalidDiceNotation, c, start+1)
+		}
+		tokens = append(tokens, token{kind: kind, text: string(c), pos: start})
+		i++
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// parser is a recursive descent parser over notation tokens.
+// The grammar, lowest precedence first:
+//
+//	expression := term (("+" | "-") term)*
+//	term       := unary (("*" | "/") unary)*
+//	unary      := ("+" | "-") unary | primary
+//	primary    := number | dice | "(" expression ")"
+//	dice       := [number] "d" (number | "%" | "f") rule*
+//	rule       := keep | explode | reroll | success | double | failure | crit
+//	keep       := ("kh" | "kl" | "dh" | "dl") [number]
+//	explode    := ("!" | "!!" | "!p") [condition]
+//	reroll     := ("r" | "ro") condition
+//	success    := ("=" | ">=" | "<=" | ">" | "<") number
+//	double     := "dbl" condition
+//	failure    := "f" condition
+//	crit       := ("cs" | "cf") condition
+//	condition  := [("=" | ">=" | "<=" | ">" | "<")] number
+//
+// As in Roll20, ">" and "<" in conditions are inclusive. "d%" is an alias
+// for d100, "df" rolls Fate dice and "d66" reads two d6 as tens and ones.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// normalizeNotation trims and lowercases user-supplied notation for parseNotation.
+func normalizeNotation(notation string) string {
+	return strings.TrimSpace(strings.ToLower(notation))
+}
+
+// parseNotation parses a dice notation string into an expression tree.
+// The notation is expected to be lowercased already.
+func parseNotation(notation string) (exprNode, error) {
+	tokens, err := tokenize(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("%w: empty notation", errInvalidDiceNotation)
+	}
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %s", errInvalidDiceNotation, tok)
+	}
+	return expr, nil
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token.
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseExpression() (exprNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenPlus && tok.kind != tokenMinus {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenStar && tok.kind != tokenSlash {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (exprNode, error) {
+	switch p.peek().kind {
+	case tokenMinus:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{operand: operand}, nil
+	case tokenPlus:
+		p.next()
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (exprNode, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenNumber:
+		p.next()
+		value, err := parseNumber(tok)
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next.kind == tokenWord && strings.HasPrefix(next.text, "d") {
+			return p.parseDice(value, tok)
+		}
+		return &numberNode{value: value}, nil
+
+	case tokenWord:
+		if strings.HasPrefix(tok.text, "d") {
+			return p.parseDice(1, tok)
+		}
+
+	case tokenLParen:
+		p.next()
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("%w: expected \")\" but found %s", errInvalidDiceNotation, closing)
+		}
+		return &groupNode{inner: inner}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unexpected %s", errInvalidDiceNotation, tok)
+}
+
+// parseDice parses a dice term. The optional count has already been consumed;
+// the current token starts with the "d".
+func (p *parser) parseDice(count int, start token) (exprNode, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("%w: invalid roll count at position %d", errInvalidDiceNotation, start.pos+1)
+	}
+	if count > maxNotationDice {
+		return nil, fmt.Errorf("%w: cannot roll more than %d dice in one term", errInvalidDiceNotation, maxNotationDice)
+	}
+	p.consumeWord("d")
+
+	node, err := p.parseDieType(uint(count))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.parseDiceSuffixes(node); err != nil {
+		return nil, err
+	}
+	if err := node.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidDiceNotation, err)
+	}
+	return node, nil
+}
+
+// parseDieType parses the die that follows the "d": a face count, "%" for
+// percentile dice, "f" for Fate dice, or 66 for d66.
+func (p *parser) parseDieType(count uint) (*diceNode, error) {
+	if p.consumeWord("f") {
+		return newFateDice(count), nil
+	}
+
+	tok := p.next()
+	if tok.kind == tokenPercent {
+		return &diceNode{count: count, faces: 100}, nil
+	}
+	if tok.kind != tokenNumber {
+		return nil, fmt.Errorf("%w: expected die faces but found %s", errInvalidDiceNotation, tok)
+	}
+	faces, err := parseNumber(tok)
+	if err != nil {
+		return nil, err
+	}
+	if faces <= 0 {
+		return nil, fmt.Errorf("%w: invalid die faces at position %d", errInvalidDiceNotation, tok.pos+1)
+	}
+	if faces == 66 {
+		return newD66Dice(count), nil
+	}
+	return &diceNode{count: count, faces: uint(faces)}, nil
+}
+
+// parseDiceSuffixes parses the optional rules that follow a dice term's faces.
+func (p *parser) parseDiceSuffixes(node *diceNode) error {
+	for {
+		switch tok := p.peek(); {
+		case tok.kind == tokenBang:
+			if err := p.parseExplode(node); err != nil {
+				return err
+			}
+
+		case tok.kind == tokenCompare:
+			target, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			node.pool.target = target
+
+		case p.consumeWord("dbl"):
+			on, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			node.pool.double = on
+
+		case p.consumeWord("cs"):
+			on, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			node.crit.success = on
+
+		case p.consumeWord("cf"):
+			on, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			node.crit.failure = on
+
+		case p.consumeWord("f"):
+			on, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			node.pool.failure = on
+
+		case tok.kind == tokenWord && strings.HasPrefix(tok.text, "r"):
+			if err := p.parseReroll(node); err != nil {
+				return err
+			}
+
+		case tok.kind == tokenWord:
+			if err := p.parseKeep(node); err != nil {
+				return err
+			}
+
+		default:
+			return nil
+		}
+	}
+}
+
+// parseKeep parses a keep/drop rule such as "kh3".
+func (p *parser) parseKeep(node *diceNode) error {
+	tok := p.peek()
+	for suffix, mode := range keepNotation {
+		if !p.consumeWord(suffix) {
+			continue
+		}
+		// The count is optional: "2d20kh" keeps the single highest die
+		n := 1
+		if p.peek().kind == tokenNumber {
+			var err error
+			if n, err = parseNumber(p.next()); err != nil {
+				return err
+			}
+		}
+		node.keep = keepRule{mode: mode, n: uint(n)}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown dice rule %s", errInvalidDiceNotation, tok)
+}
+
+// parseExplode parses an explode rule such as "!", "!!", "!p" or "!>=5".
+func (p *parser) parseExplode(node *diceNode) error {
+	p.next() // "!"
+	rule := explodeRule{mode: explodeStandard}
+	switch {
+	case p.peek().kind == tokenBang:
+		p.next()
+		rule.mode = explodeCompound
+	case p.consumeWord("p"):
+		rule.mode = explodePenetrate
+	}
+
+	if kind := p.peek().kind; kind == tokenCompare || kind == tokenNumber {
+		on, err := p.parseCondition()
+		if err != nil {
+			return err
+		}
+		rule.on = on
+	}
+	node.explode = rule
+	return nil
+}
+
+// parseReroll parses a reroll rule such as "r1" or "ro<2".
+func (p *parser) parseReroll(node *diceNode) error {
+	rule := rerollRule{once: p.consumeWord("ro")}
+	if !rule.once {
+		p.consumeWord("r")
+	}
+
+	on, err := p.parseCondition()
+	if err != nil {
+		return err
+	}
+	rule.on = on
+	node.reroll = rule
+	return nil
+}
+
+// parseCondition parses a comparison such as ">=5". A bare number such as
+// "1" matches that exact value.
+func (p *parser) parseCondition() (Condition, error) {
+	op := compareEqual
+	if tok := p.peek(); tok.kind == tokenCompare {
+		p.next()
+		op = compareNotation[tok.text]
+	}
+
+	tok := p.next()
+	if tok.kind != tokenNumber {
+		return Condition{}, fmt.Errorf("%w: expected condition value but found %s", errInvalidDiceNotation, tok)
+	}
+	value, err := parseNumber(tok)
+	if err != nil {
+		return Condition{}, err
+	}
+	return Condition{op: op, value: value}, nil
+}
+
+// consumeWord consumes prefix from the current word token, so adjacent
+// rules such as "!pkh1" can share a single word token. Returns false
+// without consuming anything if the word doesn't start with prefix.
+func (p *parser) consumeWord(prefix string) bool {
+	tok := p.peek()
+	if tok.kind != tokenWord || !strings.HasPrefix(tok.text, prefix) {
+		return false
+	}
+	if len(tok.text) == len(prefix) {
+		p.next()
+		return true
+	}
+	p.tokens[p.pos].text = tok.text[len(prefix):]
+	p.tokens[p.pos].pos += len(prefix)
+	return true
+}
+
+// parseNumber converts a number token to an int.
+func parseNumber(tok token) (int, error) {
+	value, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid number %s", errInvalidDiceNotation, tok)
+	}
+	return value, nil
+}
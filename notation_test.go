@@ -0,0 +1,218 @@
+package d20
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNotation_String(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     string
+	}{
+		{"d20", "1d20"},
+		{"2d6+3", "2d6+3"},
+		{"2d6 + 1d4 + 3", "2d6+1d4+3"},
+		{"1d8+1d6-1", "1d8+1d6-1"},
+		{"(1d8+2)*2", "(1d8+2)*2"},
+		{"-1d4", "-1d4"},
+		{"+1d4", "1d4"},
+		{"4d6/2", "4d6/2"},
+		{"4d6kh3", "4d6kh3"},
+		{"3d20kh", "3d20kh1"},
+		{"4d6dl1+2", "4d6dl1+2"},
+		{"2d20kl1", "2d20kl1"},
+		{"1d6!", "1d6!"},
+		{"2d6!!", "2d6!!"},
+		{"3d6!p", "3d6!p"},
+		{"1d10!>=9", "1d10!>=9"},
+		{"1d10! >= 9", "1d10!>=9"},
+		{"4d6!pkh3", "4d6!pkh3"},
+		{"4d6kh3!", "4d6!kh3"},
+		{"1d6!>5", "1d6!>=5"},
+		{"1d6!6", "1d6!=6"},
+		{"2d6ro<2", "2d6ro<=2"},
+		{"1d20ro1", "1d20ro=1"},
+		{"1d6r1", "1d6r=1"},
+		{"2d20ro1kh1", "2d20ro=1kh1"},
+		{"10d10>=8", "10d10>=8"},
+		{"10d10>8dbl10f1", "10d10>=8dbl=10f=1"},
+		{"10d10!>=8", "10d10!>=8"},
+		{"10d10>=8!", "10d10>=8!"},
+		{"10d10>=8f1!>=9", "10d10>=8f=1!>=9"},
+		{"2d10ro1>=8!!kh1", "2d10ro=1>=8!!kh1"},
+		{"4df", "4dF"},
+		{"df+2", "1dF+2"},
+		{"4dfkh2", "4dFkh2"},
+		{"d%", "1d100"},
+		{"2d%+5", "2d100+5"},
+		{"1d66", "1d66"},
+		{"d66", "1d66"},
+		{"1d20cs>19", "1d20cs>=19"},
+		{"1d20cs19cf<2+5", "1d20cs=19cf<=2+5"},
+		{"2d20kh1cs>=18", "2d20kh1cs>=18"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			expr, err := parseNotation(tt.notation)
+			if err != nil {
+				t.Fatalf("parseNotation(%q) error: %v", tt.notation, err)
+			}
+			if got := expr.String(); got != tt.want {
+				t.Errorf("parseNotation(%q).String() = %q, want %q", tt.notation, got, tt.want)
+			}
+
+			// String output can be rolled again, so it must mean the same
+			// roll once normalized the way Roller.Notation does
+			again := strings.ToLower(expr.String())
+			reparsed, err := parseNotation(again)
+			if err != nil {
+				t.Fatalf("parseNotation(%q) error: %v", again, err)
+			}
+			if !reflect.DeepEqual(reparsed, expr) {
+				t.Errorf("parseNotation(%q) = %#v, want %#v", again, reparsed, expr)
+			}
+		})
+	}
+}
+
+func TestParseNotation_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+	}{
+		{"Empty", ""},
+		{"Trailing operator", "1d20+"},
+		{"Unclosed paren", "(1d20+3"},
+		{"Unopened paren", "1d20+3)"},
+		{"Zero count", "0d6"},
+		{"Zero faces", "1d0"},
+		{"Missing faces", "2d"},
+		{"Unknown word", "1dabc"},
+		{"Unknown character", "1d20#3"},
+		{"Too many dice", "1001d6"},
+		{"Adjacent numbers", "1d20 3"},
+		{"Keep too many", "2d6kh3"},
+		{"Drop every die", "2d6dl2"},
+		{"Keep zero", "2d6kh0"},
+		{"Unknown rule", "2d6xy1"},
+		{"Explode without threshold value", "1d6!>="},
+		{"Dangling comparison", "1d6>"},
+		{"Reroll without condition", "1d20r"},
+		{"Reroll once without condition", "1d20ro+2"},
+		{"Double without condition", "10d10>=8dbl"},
+		{"Unknown die type", "4dx"},
+		{"Percent without dice", "2%"},
+		{"Crit range without condition", "1d20cs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseNotation(tt.notation); err == nil {
+				t.Errorf("parseNotation(%q) expected error, got none", tt.notation)
+			}
+		})
+	}
+}
+
+func TestRoller_Roll_Expression(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Every term is rolled", func(t *testing.T) {
+		result, err := roller.Roll("2d6+1d4+3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.DiceRolls) != 3 {
+			t.Fatalf("expected 3 die rolls, got %d", len(result.DiceRolls))
+		}
+		expected := result.DiceRolls[0] + result.DiceRolls[1] + result.DiceRolls[2] + 3
+		if result.Value != expected {
+			t.Errorf("expected value %d, got %d", expected, result.Value)
+		}
+	})
+
+	t.Run("Detail shows every term", func(t *testing.T) {
+		result, err := roller.Roll("1d8+1d6-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(result.Detail, "Rolled 1d8+1d6... [") {
+			t.Errorf("unexpected detail prefix: %q", result.Detail)
+		}
+		if !strings.Contains(result.Detail, "] + [") {
+			t.Errorf("detail should list both dice terms: %q", result.Detail)
+		}
+		if !strings.Contains(result.Detail, "-1 modifier") {
+			t.Errorf("detail should list the constant as a modifier: %q", result.Detail)
+		}
+	})
+
+	t.Run("Operator precedence", func(t *testing.T) {
+		result, err := roller.Roll("1d1+2*3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != 7 {
+			t.Errorf("expected 1+2*3 = 7, got %d", result.Value)
+		}
+	})
+
+	t.Run("Parentheses", func(t *testing.T) {
+		result, err := roller.Roll("(1d1+2)*3")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != 9 {
+			t.Errorf("expected (1+2)*3 = 9, got %d", result.Value)
+		}
+	})
+
+	t.Run("Division rounds down", func(t *testing.T) {
+		result, err := roller.Roll("(1d1+6)/2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != 3 {
+			t.Errorf("expected 7/2 = 3, got %d", result.Value)
+		}
+
+		result, err = roller.Roll("(1d1-8)/2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != -4 {
+			t.Errorf("expected -7/2 = -4, got %d", result.Value)
+		}
+	})
+
+	t.Run("Division by zero", func(t *testing.T) {
+		if _, err := roller.Roll("1d6/(1d1-1)"); err == nil {
+			t.Error("expected division by zero error")
+		}
+	})
+}
+
+func TestRoller_Notation_WithAdvantage(t *testing.T) {
+	roller := NewRoller(42)
+	builder, err := roller.Notation("1d20+1d4")
+	if err != nil {
+		t.Fatalf("Notation() error: %v", err)
+	}
+
+	result, err := builder.WithAdvantage().Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+
+	// Advantage applies to the first term only: two d20s plus one d4
+	if len(result.DiceRolls) != 3 {
+		t.Fatalf("expected 3 dice rolls, got %d", len(result.DiceRolls))
+	}
+	expected := max(result.DiceRolls[0], result.DiceRolls[1]) + result.DiceRolls[2]
+	if result.Value != expected {
+		t.Errorf("expected value %d, got %d", expected, result.Value)
+	}
+}
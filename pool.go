@@ -0,0 +1,217 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errPoolRuleWithoutTarget = errors.New("failure and double rules require a success target")
+	errNoSuccessTarget       = errors.New("roll does not count successes")
+)
+
+// poolRule turns a dice term into a success-counting pool, as used by
+// World of Darkness, Shadowrun and Year Zero.
+type poolRule struct {
+	target  Condition // Dice matching count as successes; the zero Condition disables counting
+	failure Condition // Dice matching subtract a success ("f1")
+	double  Condition // Successful dice matching count as two successes ("dbl10")
+}
+
+// counts reports whether the term counts successes instead of summing.
+func (p poolRule) counts() bool {
+	return !p.target.IsZero()
+}
+
+// validate checks that failure and double rules are only used with a target.
+func (p poolRule) validate() error {
+	if !p.counts() && (!p.failure.IsZero() || !p.double.IsZero()) {
+		return errPoolRuleWithoutTarget
+	}
+	return nil
+}
+
+// successes returns how many successes a single die value is worth.
+func (p poolRule) successes(value int) int {
+	switch {
+	case p.target.Matches(value) && p.double.Matches(value):
+		return 2
+	case p.target.Matches(value):
+		return 1
+	case p.failure.Matches(value):
+		return -1
+	}
+	return 0
+}
+
+// String formats the rule as a notation suffix, e.g. ">=8dbl=10f=1".
+func (p poolRule) String() string {
+	if !p.counts() {
+		return ""
+	}
+	s := p.target.String()
+	if !p.double.IsZero() {
+		s += "dbl" + p.double.String()
+	}
+	if !p.failure.IsZero() {
+		s += "f" + p.failure.String()
+	}
+	return s
+}
+
+// poolTally accumulates success-counting totals across an evaluation.
+type poolTally struct {
+	successes   int // Net successes after doubles and failures
+	successDice int // Dice that met the success target
+	failureDice int // Dice that matched the failure condition
+	ones        int // Dice showing a natural 1
+	dice        int // Dice counted, excluding dropped dice
+}
+
+// countSuccesses scores the kept dice of a pool, recording each die's
+// contribution, and returns the net successes for the term.
+func (ev *evaluation) countSuccesses(rule poolRule, dice []DieResult) int {
+	if ev.pool == nil {
+		ev.pool = &poolTally{}
+	}
+
+	net := 0
+	for i := range dice {
+		if dice[i].Dropped {
+			continue
+		}
+		dice[i].Successes = rule.successes(dice[i].Value)
+		net += dice[i].Successes
+
+		ev.pool.dice++
+		if dice[i].Successes > 0 {
+			ev.pool.successDice++
+		}
+		if dice[i].Successes < 0 {
+			ev.pool.failureDice++
+		}
+		if dice[i].Value == 1 {
+			ev.pool.ones++
+		}
+	}
+	ev.pool.successes += net
+	return net
+}
+
+// botch reports a World of Darkness botch: no successes and at least one 1.
+func (t *poolTally) botch() bool {
+	return t.successDice == 0 && t.ones > 0
+}
+
+// glitch reports a Shadowrun glitch: more than half the dice show 1.
+func (t *poolTally) glitch() bool {
+	return t.ones*2 > t.dice
+}
+
+// notes describes the pool result for the detail string.
+func (t *poolTally) notes() []string {
+	notes := []string{fmt.Sprintf("%d successes", t.successes)}
+	if t.successes == 1 {
+		notes[0] = "1 success"
+	}
+	switch {
+	case t.glitch() && t.successDice == 0:
+		notes = append(notes, "*Critical glitch!*")
+	case t.glitch():
+		notes = append(notes, "*Glitch!*")
+	}
+	if t.botch() {
+		notes = append(notes, "*Botch!*")
+	}
+	return notes
+}
+
+// PoolOutcome is the result of a success-counting dice pool roll.
+// The embedded RollOutcome's Value is the net number of successes plus any
+// modifiers, and each die's contribution is recorded in Dice[i].Successes.
+type PoolOutcome struct {
+	RollOutcome
+	Successes      int  // Net successes after doubles and failures
+	SuccessDice    int  // Dice that met the success target
+	FailureDice    int  // Dice that matched the failure condition and subtracted a success
+	Ones           int  // Dice showing a natural 1
+	Botch          bool // No dice succeeded and at least one showed 1 (World of Darkness)
+	Glitch         bool // More than half the dice showed 1 (Shadowrun)
+	CriticalGlitch bool // A glitch with no successful dice (Shadowrun)
+}
+
+// CountSuccesses turns the roll into a dice pool that counts dice showing
+// target or higher as successes instead of summing them.
+// Equivalent to a comparison after the dice, e.g. "10d10>=8".
+//
+// Example:
+//
+//	pool, _ := roller.Dice(10, 10).CountSuccesses(8).RollPool()
+//	fmt.Println(pool.Successes)
+func (rb *RollBuilder) CountSuccesses(target int) *RollBuilder {
+	return rb.SuccessOn(AtLeast(target))
+}
+
+// SuccessOn counts dice matching the condition as successes, for pools that
+// succeed on something other than a minimum value, such as roll-under systems.
+func (rb *RollBuilder) SuccessOn(on Condition) *RollBuilder {
+	rb.primary.pool.target = on
+	return rb
+}
+
+// DoubleOn counts successful dice matching the condition as two successes,
+// e.g. DoubleOn(d20.Equals(10)) for "10s count double".
+// Equivalent to the "dbl" notation suffix, e.g. "10d10>=8dbl10".
+func (rb *RollBuilder) DoubleOn(on Condition) *RollBuilder {
+	rb.primary.pool.double = on
+	return rb
+}
+
+// FailuresOn makes dice matching the condition subtract a success,
+// e.g. FailuresOn(d20.Equals(1)) for "1s subtract a success".
+// Equivalent to the "f" notation suffix, e.g. "10d10>=8f1".
+func (rb *RollBuilder) FailuresOn(on Condition) *RollBuilder {
+	rb.primary.pool.failure = on
+	return rb
+}
+
+// RollPool executes a success-counting roll and returns the dedicated
+// PoolOutcome with success counts and botch/glitch flags.
+// Returns an error if no dice term counts successes.
+func (rb *RollBuilder) RollPool() (PoolOutcome, error) {
+	outcome, ev, err := rb.roll()
+	if err != nil {
+		return PoolOutcome{}, err
+	}
+	if ev.pool == nil {
+		return PoolOutcome{}, errNoSuccessTarget
+	}
+
+	return PoolOutcome{
+		RollOutcome:    outcome,
+		Successes:      ev.pool.successes,
+		SuccessDice:    ev.pool.successDice,
+		FailureDice:    ev.pool.failureDice,
+		Ones:           ev.pool.ones,
+		Botch:          ev.pool.botch(),
+		Glitch:         ev.pool.glitch(),
+		CriticalGlitch: ev.pool.glitch() && ev.pool.successDice == 0,
+	}, nil
+}
+
+// RollPool parses success-counting dice notation and rolls it, returning a
+// PoolOutcome. See Roll for the notation syntax; a comparison after the dice
+// such as "10d10>=8" turns the term into a pool.
+//
+// Examples:
+//   - "10d10>=8" - Count dice showing 8 or higher
+//   - "10d10>=8dbl10" - 10s count as two successes
+//   - "10d10>=8f1" - 1s subtract a success
+//   - "12d6>=5" - Shadowrun hits, with glitch detection
+func (r *Roller) RollPool(notation string) (PoolOutcome, error) {
+	builder, err := r.Notation(notation)
+	if err != nil {
+		return PoolOutcome{}, err
+	}
+	return builder.RollPool()
+}
@@ -0,0 +1,101 @@
+package d20
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoller_RollPool(t *testing.T) {
+	roller := NewRoller(42)
+
+	tests := []struct {
+		name               string
+		notation           string
+		wantSuccesses      int
+		wantBotch          bool
+		wantGlitch         bool
+		wantCriticalGlitch bool
+	}{
+		// Every d1 shows a 1, so each roll glitches
+		{"Every die succeeds", "5d1>=1", 5, false, true, false},
+		{"No die succeeds", "5d1>=2", 0, true, true, true},
+		{"Doubles", "3d1>=1dbl1", 6, false, true, false},
+		{"Failures subtract", "3d1>=2f1", -3, true, true, true},
+		{"Modifier adds to successes", "2d1>=1+1", 3, false, true, false},
+		{"Exploded dice count", "1d1!1>=1", 101, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := roller.RollPool(tt.notation)
+			if err != nil {
+				t.Fatalf("RollPool(%q) error: %v", tt.notation, err)
+			}
+			if pool.Value != tt.wantSuccesses {
+				t.Errorf("expected value %d, got %d", tt.wantSuccesses, pool.Value)
+			}
+			if pool.Botch != tt.wantBotch {
+				t.Errorf("expected botch %v, got %v", tt.wantBotch, pool.Botch)
+			}
+			if pool.Glitch != tt.wantGlitch {
+				t.Errorf("expected glitch %v, got %v", tt.wantGlitch, pool.Glitch)
+			}
+			if pool.CriticalGlitch != tt.wantCriticalGlitch {
+				t.Errorf("expected critical glitch %v, got %v", tt.wantCriticalGlitch, pool.CriticalGlitch)
+			}
+		})
+	}
+}
+
+func TestRollBuilder_CountSuccesses(t *testing.T) {
+	roller := NewRoller(42)
+
+	for range 20 {
+		pool, err := roller.Dice(10, 10).CountSuccesses(8).FailuresOn(Equals(1)).RollPool()
+		if err != nil {
+			t.Fatalf("RollPool() error: %v", err)
+		}
+		if len(pool.DiceRolls) != 10 {
+			t.Fatalf("expected 10 dice, got %d", len(pool.DiceRolls))
+		}
+
+		successes, ones := 0, 0
+		for i, die := range pool.Dice {
+			want := 0
+			switch {
+			case die.Value >= 8:
+				want = 1
+				successes++
+			case die.Value == 1:
+				want = -1
+				ones++
+			}
+			if die.Successes != want {
+				t.Errorf("die %d showing %d: expected %d successes, got %d", i, die.Value, want, die.Successes)
+			}
+		}
+		if pool.Successes != successes-ones || pool.Value != pool.Successes {
+			t.Errorf("expected %d net successes, got %d (value %d)", successes-ones, pool.Successes, pool.Value)
+		}
+		if pool.SuccessDice != successes || pool.FailureDice != ones || pool.Ones != ones {
+			t.Errorf("unexpected tallies: %+v", pool)
+		}
+		if !strings.Contains(pool.Detail, "success") {
+			t.Errorf("expected successes in detail, got %q", pool.Detail)
+		}
+	}
+}
+
+func TestRollBuilder_RollPool_Errors(t *testing.T) {
+	roller := NewRoller(42)
+
+	if _, err := roller.Dice(2, 6).RollPool(); err == nil {
+		t.Error("expected error for roll without success target")
+	}
+	if _, err := roller.Dice(2, 6).FailuresOn(Equals(1)).Roll(); err == nil {
+		t.Error("expected error for failure rule without success target")
+	}
+	if _, err := roller.RollPool("10d10f1"); err == nil {
+		t.Error("expected error for notation failure rule without success target")
+	}
+}
@@ -0,0 +1,123 @@
+package d20
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"math/rand"
+	randv2 "math/rand/v2"
+)
+
+// RandomSource supplies the random numbers behind every die a Roller rolls.
+// Implementations must return uniformly distributed values.
+// Sources from math/rand/v2, such as rand.New(rand.NewPCG(1, 2)), satisfy
+// RandomSource directly.
+type RandomSource interface {
+	// IntN returns a random integer in [0, n). n is always greater than 0.
+	IntN(n int) int
+}
+
+// NewSeededSource returns the math/rand source NewRoller uses, so rolls are
+// reproducible for a given seed and match earlier versions of this package.
+func NewSeededSource(seed int64) RandomSource {
+	return newSeededSource(seed, 0)
+}
+
+// NewPCGSource returns a math/rand/v2 PCG source with the given seeds.
+// PCG is fast and has better statistical quality than NewSeededSource.
+func NewPCGSource(seed1, seed2 uint64) RandomSource {
+	key := sha256.Sum256(binary.BigEndian.AppendUint64(binary.BigEndian.AppendUint64([]byte("pcg"), seed1), seed2))
+	return newStateSource(randv2.NewPCG(seed1, seed2), key)
+}
+
+// NewChaCha8Source returns a math/rand/v2 ChaCha8 source with the given seed.
+// ChaCha8 is cryptographically strong yet reproducible from its seed.
+func NewChaCha8Source(seed [32]byte) RandomSource {
+	return newStateSource(randv2.NewChaCha8(seed), seed)
+}
+
+// NewCryptoSource returns a source backed by crypto/rand, for public games
+// where rolls must be unpredictable. Rolls from it can't be reproduced.
+func NewCryptoSource() RandomSource {
+	return randv2.New(cryptoSource{})
+}
+
+// seededSource adapts a math/rand generator to RandomSource. The generator's
+// state can't be read, so it counts draws from the underlying source so the
+// stream position can be restored by replaying them.
+type seededSource struct {
+	seed  int64
+	draws uint64 // Values drawn from the underlying source so far
+	rng   *rand.Rand
+}
+
+// newSeededSource creates a seeded source advanced past draws values.
+func newSeededSource(seed int64, draws uint64) *seededSource {
+	s := &seededSource{seed: seed}
+	src := rand.NewSource(seed)
+	for range draws {
+		src.Int63()
+	}
+	s.draws = draws
+	s.rng = rand.New(&drawCounter{Source: src, draws: &s.draws})
+	return s
+}
+
+func (s *seededSource) IntN(n int) int {
+	return s.rng.Intn(n)
+}
+
+func (s *seededSource) deriveKey() [32]byte {
+	return sha256.Sum256(binary.BigEndian.AppendUint64([]byte("seeded"), uint64(s.seed)))
+}
+
+// drawCounter counts the values drawn from a math/rand source.
+type drawCounter struct {
+	rand.Source
+	draws *uint64
+}
+
+func (c *drawCounter) Int63() int64 {
+	*c.draws++
+	return c.Source.Int63()
+}
+
+// stateSource is a math/rand/v2 source whose state can be saved and restored,
+// such as PCG or ChaCha8.
+type stateSource interface {
+	randv2.Source
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// stateRand adapts a stateSource to RandomSource, keeping the source so its
+// state can be snapshotted.
+type stateRand struct {
+	*randv2.Rand
+	src stateSource
+	key [32]byte // Derivation key from the source's original seed
+}
+
+func newStateSource(src stateSource, key [32]byte) *stateRand {
+	return &stateRand{Rand: randv2.New(src), src: src, key: key}
+}
+
+func (s *stateRand) deriveKey() [32]byte {
+	return s.key
+}
+
+// keyedSource is implemented by sources created from a known seed. Its
+// derivation key, computed from that seed, roots the streams of Roller.Derive.
+type keyedSource interface {
+	deriveKey() [32]byte
+}
+
+// cryptoSource is a math/rand/v2 source that reads from crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	_, _ = crand.Read(b[:]) // crypto/rand.Read never returns an error
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,93 @@
+package d20
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRandomSources(t *testing.T) {
+	tests := []struct {
+		name      string
+		newSource func() RandomSource
+		seeded    bool
+	}{
+		{"Seeded", func() RandomSource { return NewSeededSource(42) }, true},
+		{"PCG", func() RandomSource { return NewPCGSource(1, 2) }, true},
+		{"ChaCha8", func() RandomSource { return NewChaCha8Source([32]byte{42}) }, true},
+		{"Crypto", NewCryptoSource, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := NewRollerWithSource(tt.newSource())
+			second := NewRollerWithSource(tt.newSource())
+
+			var firstRolls, secondRolls []int
+			for range 50 {
+				a, err := first.Dice(3, 6).Roll()
+				if err != nil {
+					t.Fatalf("Roll() error: %v", err)
+				}
+				b, err := second.Dice(3, 6).Roll()
+				if err != nil {
+					t.Fatalf("Roll() error: %v", err)
+				}
+				firstRolls = append(firstRolls, a.DiceRolls...)
+				secondRolls = append(secondRolls, b.DiceRolls...)
+			}
+
+			for _, v := range firstRolls {
+				if v < 1 || v > 6 {
+					t.Fatalf("roll %d out of range for a d6", v)
+				}
+			}
+			if tt.seeded && !slices.Equal(firstRolls, secondRolls) {
+				t.Error("rollers with the same seed should roll the same dice")
+			}
+		})
+	}
+}
+
+func TestNewRoller_MatchesSeededSource(t *testing.T) {
+	a, err := NewRoller(7).Roll("4d6kh3+1d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	b, err := NewRollerWithSource(NewSeededSource(7)).Roll("4d6kh3+1d20")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if !slices.Equal(a.DiceRolls, b.DiceRolls) {
+		t.Errorf("NewRoller rolled %v, seeded source rolled %v", a.DiceRolls, b.DiceRolls)
+	}
+}
+
+// countingSource returns 0 for every draw and records the sizes requested.
+type countingSource struct {
+	sizes []int
+}
+
+func (s *countingSource) IntN(n int) int {
+	s.sizes = append(s.sizes, n)
+	return 0
+}
+
+func TestRandomSource_D100SkillCheck(t *testing.T) {
+	source := &countingSource{}
+	actor, err := NewActor("investigator").WithHP(10).WithAttribute("stealth", 45).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	_, outcome, err := actor.D100SkillCheck("stealth", NewRollerWithSource(source), 1)
+	if err != nil {
+		t.Fatalf("D100SkillCheck() error: %v", err)
+	}
+	// Two tens dice for the bonus die, then the ones die, all drawn from the source
+	if !slices.Equal(source.sizes, []int{10, 10, 10}) {
+		t.Errorf("source draws = %v, want three d10 draws", source.sizes)
+	}
+	if outcome.Value != 100 {
+		t.Errorf("00 + 0 should read as 100, got %d", outcome.Value)
+	}
+}
@@ -2,58 +2,158 @@ package d20
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
 // RollOutcome is the complete result of a dice roll operation.
 type RollOutcome struct {
-	Value     int    // Final calculated result (dice total + modifiers)
-	DiceRolls []int  // Raw values from each die rolled
-	Detail    string // Formatted roll description in Bioware style
+	Value      int          // Final calculated result (dice total + modifiers)
+	DiceRolls  []int        // Raw values from each die rolled
+	Dice       []DieResult  // Each die rolled, in the same order as DiceRolls
+	Terms      []TermResult // Each dice term of the expression, indexed by DieResult.Term
+	Modifiers  []Modifier   // Modifiers added to the dice total, with their reasons and any rolled values
+	Suppressed []Modifier   // Modifiers that did not apply under the roll's stacking policy
+	Detail     string       // Formatted roll description in Bioware style
+	Natural    int          // Kept die of the first dice term before modifiers; 0 if it kept more than one die
+	IsCritical bool         // Natural result is in the crit range (a natural 20 on a d20 by default)
+	IsFumble   bool         // Natural result is in the fumble range (a natural 1 by default)
+}
+
+// DieResult describes a single die rolled as part of a RollOutcome.
+type DieResult struct {
+	Faces      uint    `json:"faces"`                // Number of distinct faces on the die (3 for Fate dice, 36 for d66)
+	Kind       DieKind `json:"kind,omitempty"`       // How the die's faces are numbered
+	Value      int     `json:"value"`                // Value the die contributes (the running total for compounding dice)
+	Raw        int     `json:"raw"`                  // Face rolled, before penetration subtracts 1 or compounding adds extra rolls
+	Term       int     `json:"term"`                 // Index into RollOutcome.Terms of the dice term that rolled the die
+	Dropped    bool    `json:"dropped,omitempty"`    // True if the die was discarded by advantage/disadvantage or keep/drop rules
+	Exploded   bool    `json:"exploded,omitempty"`   // True if the die was added by an exploding or penetrating die
+	Compounded int     `json:"compounded,omitempty"` // Number of extra rolls added into Value by a compounding die
+	Rerolled   []int   `json:"rerolled,omitempty"`   // Values the die showed before being rerolled, in order
+	Successes  int     `json:"successes,omitempty"`  // Successes counted in a pool: 1, 2 when doubled, -1 for a failure
+}
+
+// TermResult describes one dice term of a rolled expression, such as the
+// "1d4" in "1d20+1d4+5".
+type TermResult struct {
+	Notation string // The term in dice notation, e.g. "4d6kh3"
+	Value    int    // Total of the term's kept dice, or its net successes for a pool
 }
 
 // NewRollOutcome creates a new RollOutcome with formatted detail string.
 // The detail string follows Bioware-style formatting:
 // "Rolled 2d20... 16, 12; +3 strength, +2 proficiency; *Result: 33*"
 func NewRollOutcome(rollCount uint, dieFaces uint, rolls []int, modifiers []Modifier, finalValue int) RollOutcome {
+	dice := make([]DieResult, len(rolls))
+	total := 0
+	for i, r := range rolls {
+		dice[i] = DieResult{Faces: dieFaces, Value: r, Raw: r}
+		total += r
+	}
+	notation := fmt.Sprintf("%dd%d", rollCount, dieFaces)
+	outcome := newRollOutcome(notation, formatDice(dice), dice, modifiers, nil, finalValue)
+	outcome.Terms = []TermResult{{Notation: notation, Value: total}}
+	return outcome
+}
+
+// newRollOutcome creates a RollOutcome from structured dice results.
+// The formatted dice string is passed separately so expressions can show
+// how their terms combine. Steps that scale the total, such as a critical
+// or resistance, are shown before the result, and notes after it.
+func newRollOutcome(notation string, formattedDice string, dice []DieResult, modifiers []Modifier, steps []string, finalValue int, notes ...string) RollOutcome {
+	rolls := make([]int, len(dice))
+	for i, die := range dice {
+		rolls[i] = die.Value
+	}
 	return RollOutcome{
 		Value:     finalValue,
 		DiceRolls: rolls,
-		Detail:    formatRollDetail(rollCount, dieFaces, rolls, modifiers, finalValue),
+		Dice:      dice,
+		Modifiers: slices.Clone(modifiers),
+		Detail:    formatDetail(notation, formattedDice, modifiers, steps, finalValue, notes...),
 	}
 }
 
-// formatRollDetail creates a display-formatted string for a roll result.
-func formatRollDetail(rollCount uint, dieFaces uint, rolls []int, modifiers []Modifier, finalValue int) string {
+// Kept returns the values of the dice that counted toward the result,
+// excluding dice dropped by advantage/disadvantage or keep/drop rules.
+func (o RollOutcome) Kept() []int {
+	var kept []int
+	for _, die := range o.Dice {
+		if !die.Dropped {
+			kept = append(kept, die.Value)
+		}
+	}
+	return kept
+}
+
+// formatDice formats die values as a comma-separated list.
+// Dropped dice are struck through, e.g. "5, 3, ~~1~~, 6". Dice that exploded
+// are marked "!" and are followed by the dice they added; compounded dice
+// are marked "!!". Rerolled dice list the values they replaced, e.g. "5 (rerolled 1)".
+// Fate dice are shown as "[+]", "[-]" and "[ ]".
+func formatDice(dice []DieResult) string {
+	strs := make([]string, len(dice))
+	for i, die := range dice {
+		strs[i] = fmt.Sprintf("%d", die.Value)
+		if symbol, ok := fateSymbols[die.Value]; ok && die.Kind == FateDie && die.Compounded == 0 {
+			strs[i] = symbol
+		}
+		if die.Compounded > 0 {
+			strs[i] += "!!"
+		} else if i+1 < len(dice) && dice[i+1].Exploded {
+			strs[i] += "!"
+		}
+		if len(die.Rerolled) > 0 {
+			strs[i] += " (rerolled " + joinInts(die.Rerolled) + ")"
+		}
+		if die.Dropped {
+			strs[i] = "~~" + strs[i] + "~~"
+		}
+	}
+	return strings.Join(strs, ", ")
+}
+
+// formatDetail assembles the Bioware-style detail string from the rolled
+// notation, the formatted dice values, modifiers, scaling steps such as
+// "resistance x1/2 = 7", final result and any notes such as "2 successes".
+func formatDetail(notation string, dice string, modifiers []Modifier, steps []string, finalValue int, notes ...string) string {
 	// Start with dice notation (e.g., "Rolled 2d20...")
-	result := fmt.Sprintf("Rolled %dd%d...", rollCount, dieFaces)
+	result := fmt.Sprintf("Rolled %s...", notation)
 
 	// Individual die values
-	if len(rolls) > 0 {
-		rollStrs := make([]string, len(rolls))
-		for i, r := range rolls {
-			rollStrs[i] = fmt.Sprintf("%d", r)
-		}
-		result += " " + strings.Join(rollStrs, ", ")
+	if dice != "" {
+		result += " " + dice
 	}
 
 	// Modifiers
 	if len(modifiers) > 0 {
 		modStrs := make([]string, len(modifiers))
 		for i, mod := range modifiers {
-			var sign string
-			val := mod.Value
-			if val < 0 {
-				sign = "" // val is already negative, will display as -X
-			} else {
-				sign = "+"
-			}
-			modStrs[i] = fmt.Sprintf("%s%d %s", sign, val, strings.ToLower(mod.Reason))
+			modStrs[i] = mod.String()
 		}
 		result += "; " + strings.Join(modStrs, ", ")
 	}
 
+	// Scaling steps
+	for _, step := range steps {
+		result += "; " + step
+	}
+
 	// Final result
 	result += "; *Result: " + fmt.Sprintf("%d*", finalValue)
+
+	for _, note := range notes {
+		result += "; " + note
+	}
 	return result
 }
+
+// joinInts formats integers as a comma-separated list.
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(strs, ", ")
+}
@@ -1,37 +1,64 @@
 package d20
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
-	"math/rand"
-	"regexp"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// Roller handles dice rolling with a seedable random number generator.
+// Roller handles dice rolling with a pluggable RandomSource.
+//
+// A Roller is safe for concurrent use. Each roll holds the Roller for its
+// whole duration, so the dice of one roll are never interleaved with another.
+// Concurrent rolls on a shared Roller happen in an unpredictable order, so
+// use Fork to give each goroutine its own reproducible stream.
 type Roller struct {
-	rng *rand.Rand
+	mu        sync.Mutex // Guards source and history for the duration of each roll
+	source    RandomSource
+	history   *History     // Records every roll when set
+	deriveKey *[32]byte    // Root of Derive streams for sources without a known seed
+	seeds     RandomSource // Seeds Fork and Derive for sources whose values are queued for rolls
 }
 
 // RollBuilder provides a fluent API for configuring and executing dice rolls.
 // Use Dice() to start building a roll, chain configuration methods, then call Roll() to execute.
 type RollBuilder struct {
-	roller        *Roller
-	rollCount     uint
-	dieFaces      uint
-	modifiers     []Modifier
-	advantageType AdvantageType
+	roller          *Roller
+	expr            exprNode  // Dice expression to roll, excluding flat modifiers
+	primary         *diceNode // First dice term; advantage/disadvantage applies here
+	modifiers       []Modifier
+	advantages      []AdvantageSource // Reasons the roll has advantage or disadvantage
+	advantagePolicy AdvantagePolicy   // Resolves advantages; nil means CancelAdvantage
+	actorID         string            // Actor recorded in the roll history
+	label           string            // Label recorded in the roll history
+	successRule     SuccessRule       // How Against decides success
+	stacking        StackingPolicy    // Decides which modifiers apply; nil means StackAll
+	critDamage      CritDamage        // How damage is scaled for a critical hit
+	multipliers     []DamageMultiplier
 }
 
 // NewRoller creates a new Roller with the given seed.
 // Use the same seed to get reproducible results, or use time.Now().UnixNano()
 // for non-deterministic random rolling.
 func NewRoller(seed int64) *Roller {
-	return &Roller{
-		rng: rand.New(rand.NewSource(seed)),
+	return NewRollerWithSource(NewSeededSource(seed))
+}
+
+// NewRollerWithSource creates a new Roller that draws every die from source.
+// A nil source behaves like NewRandomRoller.
+//
+// Example:
+//
+//	roller := d20.NewRollerWithSource(d20.NewCryptoSource())
+//	roller := d20.NewRollerWithSource(d20.NewPCGSource(1, 2))
+func NewRollerWithSource(source RandomSource) *Roller {
+	if source == nil {
+		source = NewSeededSource(time.Now().UnixNano())
 	}
+	return &Roller{source: source}
 }
 
 // NewRandomRoller is a convenience function that creates a new Roller seeded
@@ -46,11 +73,9 @@ var (
 	errInvalidDiceNotation = errors.New("invalid dice notation format")
 )
 
-// diceNotationRegex matches patterns like: 1d20, 2d6+3, 3d8-2, d20+5
-var diceNotationRegex = regexp.MustCompile(`^(\d*)d(\d+)(([+-])(\d+))?$`)
-
 // Roll provides a simple shorthand API for rolling dice using standard dice notation.
-// Accepts strings like "1d20", "2d6+3", "3d8-2", or "d20" (assumes 1d20).
+// Accepts any arithmetic expression of dice terms and constants using
+// +, -, * and / with parentheses. Division rounds down.
 // This is a convenience method that doesn't use the fluent API.
 //
 // Examples:
@@ -58,52 +83,154 @@ var diceNotationRegex = regexp.MustCompile(`^(\d*)d(\d+)(([+-])(\d+))?$`)
 //   - "2d6+3" - Roll two 6-sided dice and add 3
 //   - "3d8-2" - Roll three 8-sided dice and subtract 2
 //   - "d20" - Roll one 20-sided die (shorthand)
+//   - "2d6+1d4+3" - Roll two 6-sided dice and one 4-sided die, then add 3
+//   - "(1d8+2)*2" - Roll one 8-sided die, add 2, and double the total
+//   - "4dF" - Roll four Fate dice, each showing -1, 0 or +1
+//   - "d%" - Roll percentile dice, the same as 1d100
+//   - "1d66" - Roll two 6-sided dice read as tens and ones (11 to 66)
+//
+// Constants added or subtracted at the top level of the expression are reported
+// as modifiers in the outcome. Every dice term is shown in the Detail string.
 //
 // Returns a RollOutcome with the result, or an error if the notation is invalid.
 func (r *Roller) Roll(notation string) (RollOutcome, error) {
-	notation = strings.TrimSpace(strings.ToLower(notation))
+	builder, err := r.Notation(notation)
+	if err != nil {
+		return RollOutcome{}, err
+	}
+	return builder.Roll()
+}
+
+// Notation parses dice notation into a RollBuilder, so rolls described by
+// notation can be configured further with the fluent API before rolling.
+// See Roll for the accepted syntax. Advantage and disadvantage apply to the
+// first dice term in the expression.
+//
+// Example:
+//
+//	builder, err := roller.Notation("1d20+5")
+//	result, err := builder.WithAdvantage().Roll()
+func (r *Roller) Notation(notation string) (*RollBuilder, error) {
+	notation = normalizeNotation(notation)
 
-	matches := diceNotationRegex.FindStringSubmatch(notation)
-	if matches == nil {
-		return RollOutcome{}, fmt.Errorf("%w: %s", errInvalidDiceNotation, notation)
+	expr, err := parseNotation(notation)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse roll count (default to 1 if not specified)
-	rollCount := uint(1)
-	if matches[1] != "" {
-		count, err := strconv.Atoi(matches[1])
-		if err != nil || count <= 0 {
-			return RollOutcome{}, fmt.Errorf("%w: invalid roll count", errInvalidDiceNotation)
-		}
-		rollCount = uint(count)
+	primary := firstDice(expr)
+	if primary == nil {
+		return nil, fmt.Errorf("%w: no dice in %s", errInvalidDiceNotation, notation)
 	}
 
-	// Parse die faces
-	dieFaces, err := strconv.Atoi(matches[2])
-	if err != nil || dieFaces <= 0 {
-		return RollOutcome{}, fmt.Errorf("%w: invalid die faces", errInvalidDiceNotation)
+	dice, modifiers := splitModifiers(expr)
+	builder := &RollBuilder{
+		roller:    r,
+		expr:      dice,
+		primary:   primary,
+		modifiers: []Modifier{},
+	}
+	for _, mod := range modifiers {
+		builder = builder.WithModifier(mod.Reason, mod.Value)
 	}
 
-	// Parse modifier (if present)
-	var modifiers []Modifier
-	if matches[3] != "" {
-		modValue, err := strconv.Atoi(matches[5])
-		if err != nil {
-			return RollOutcome{}, fmt.Errorf("%w: invalid modifier value", errInvalidDiceNotation)
+	return builder, nil
+}
+
+// Fork creates a new Roller with its own stream, seeded from this Roller.
+// Forking in a fixed order, such as one fork per worker goroutine at startup,
+// keeps every stream reproducible from the original seed no matter how the
+// goroutines are scheduled afterward. Forks share this Roller's history.
+//
+// Forking a scripted Roller leaves the Script's values for its own rolls;
+// the fork rolls random dice from a fixed seed.
+//
+// Example:
+//
+//	roller := d20.NewRoller(42)
+//	for i := range workers {
+//		go handle(requests[i], roller.Fork())
+//	}
+func (r *Roller) Fork() *Roller {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fork := NewRollerWithSource(NewChaCha8Source(r.drawSeed()))
+	fork.history = r.history
+	return fork
+}
+
+// Derive creates a new Roller with its own stream, computed from this
+// Roller's seed and label alone. Unlike Fork, the stream doesn't depend on
+// how many rolls or derivations happened before, so giving each actor or
+// subsystem its own derived Roller keeps its rolls stable in a seeded replay
+// even when rolls elsewhere change. The same label always derives the same
+// stream, and derived Rollers can derive further streams of their own.
+//
+// Rollers whose source has no known seed, such as NewCryptoSource, draw a
+// derivation key from their source on the first call. A scripted Roller
+// derives from a fixed key instead, leaving the Script's values for its own
+// rolls. Derived Rollers share this Roller's history.
+//
+// Example:
+//
+//	roller := d20.NewRoller(42)
+//	goblinRoller := roller.Derive("goblin_1")
+//	lootRoller := roller.Derive("loot")
+func (r *Roller) Derive(label string) *Roller {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var key [32]byte
+	switch source := r.source.(type) {
+	case keyedSource:
+		key = source.deriveKey()
+	case failingSource:
+		key = scriptedDeriveKey
+	default:
+		if r.deriveKey == nil {
+			seed := r.drawSeed()
+			r.deriveKey = &seed
 		}
-		if matches[4] == "-" {
-			modValue = -modValue
+		key = *r.deriveKey
+	}
+
+	derived := NewRollerWithSource(NewChaCha8Source(sha256.Sum256(append(key[:], label...))))
+	derived.history = r.history
+	return derived
+}
+
+// scriptedDeriveKey roots the Derive streams of scripted Rollers, whose
+// values are queued for rolls and can't be spent on a key.
+var scriptedDeriveKey = sha256.Sum256([]byte("d20 scripted derive"))
+
+// drawSeed draws a 32-byte seed from the Roller's source.
+// Must be called with r.mu held.
+func (r *Roller) drawSeed() [32]byte {
+	source := r.source
+	if _, ok := source.(failingSource); ok {
+		// A Script's values are queued for the test's rolls, so seeds come
+		// from a fixed stream instead, keeping scripted forks reproducible
+		if r.seeds == nil {
+			r.seeds = NewChaCha8Source(sha256.Sum256([]byte("d20 scripted seeds")))
 		}
-		modifiers = append(modifiers, NewModifier("modifier", modValue))
+		source = r.seeds
 	}
 
-	// Use the fluent API internally
-	builder := r.Dice(rollCount, uint(dieFaces))
-	for _, mod := range modifiers {
-		builder = builder.WithModifier(mod.Reason, mod.Value)
+	var seed [32]byte
+	for i := range seed {
+		seed[i] = byte(source.IntN(256))
 	}
+	return seed
+}
 
-	return builder.Roll()
+// sourceErr returns any error the random source recorded during the last
+// roll, such as an exhausted Script. Must be called with r.mu held.
+func (r *Roller) sourceErr() error {
+	if source, ok := r.source.(failingSource); ok {
+		return source.takeErr()
+	}
+	return nil
 }
 
 // Dice starts building a dice roll with the specified count and faces.
@@ -113,12 +240,32 @@ func (r *Roller) Roll(notation string) (RollOutcome, error) {
 //
 //	result, err := roller.Dice(1, 20).WithModifier("strength", 3).Roll()
 func (r *Roller) Dice(rollCount uint, dieFaces uint) *RollBuilder {
+	return r.newBuilder(&diceNode{count: rollCount, faces: dieFaces})
+}
+
+// Fate creates a new roll builder for Fate/Fudge dice, each showing -1, 0 or +1.
+// Equivalent to "NdF" notation.
+//
+// Example:
+//
+//	result, _ := roller.Fate(4).WithModifier("careful", 2).Roll()
+func (r *Roller) Fate(rollCount uint) *RollBuilder {
+	return r.newBuilder(newFateDice(rollCount))
+}
+
+// D66 creates a new roll builder for d66 dice, each rolling two d6 read as
+// tens and ones (11 to 66) for lookup tables. Equivalent to "Nd66" notation.
+func (r *Roller) D66(rollCount uint) *RollBuilder {
+	return r.newBuilder(newD66Dice(rollCount))
+}
+
+// newBuilder creates a roll builder for a single dice term.
+func (r *Roller) newBuilder(dice *diceNode) *RollBuilder {
 	return &RollBuilder{
-		roller:        r,
-		rollCount:     rollCount,
-		dieFaces:      dieFaces,
-		modifiers:     []Modifier{},
-		advantageType: Normal,
+		roller:    r,
+		expr:      dice,
+		primary:   dice,
+		modifiers: []Modifier{},
 	}
 }
 
@@ -133,6 +280,24 @@ func (rb *RollBuilder) WithModifier(name string, value int) *RollBuilder {
 	return rb
 }
 
+// WithDiceModifier adds a modifier that rolls dice notation for its value,
+// such as "1d4" for Bless or "1d8" for Bardic Inspiration. The dice are
+// rolled with the roll and shown in the detail, e.g. "+1d4 (3) bless".
+// Invalid notation is reported when the roll is made.
+//
+// Example:
+//
+//	roller.Dice(1, 20).WithModifier("strength", 3).WithDiceModifier("bless", "1d4").Roll()
+func (rb *RollBuilder) WithDiceModifier(name string, notation string) *RollBuilder {
+	mod, err := NewDiceModifier(name, notation)
+	if err != nil {
+		// Keep the notation so the roll reports the error
+		mod = Modifier{Reason: strings.ToLower(name), Dice: normalizeNotation(notation)}
+	}
+	rb.modifiers = append(rb.modifiers, mod)
+	return rb
+}
+
 // WithModifiers adds multiple modifiers to the roll at once.
 // Accepts a map of name->value pairs. Names are automatically lowercased.
 //
@@ -147,36 +312,177 @@ func (rb *RollBuilder) WithModifiers(modifiers map[string]int) *RollBuilder {
 	return rb
 }
 
-// WithAdvantage sets the roll to use advantage (roll twice, take higher).
-// This is a D&D 5e mechanic.
+// WithAdvantage gives the roll advantage (roll twice, take higher), with
+// optional reasons shown in the detail. This is a D&D 5e mechanic.
+// Advantage sources stack with any disadvantage sources and are resolved
+// by the roll's AdvantagePolicy, so by default advantage and disadvantage
+// cancel out to a normal roll.
 //
 // Example:
 //
 //	roller.Dice(1, 20).WithAdvantage().Roll()
-func (rb *RollBuilder) WithAdvantage() *RollBuilder {
-	rb.advantageType = Advantage
+//	roller.Dice(1, 20).WithAdvantage("flanking").WithDisadvantage("poisoned").Roll() // Normal roll
+func (rb *RollBuilder) WithAdvantage(reasons ...string) *RollBuilder {
+	return rb.addAdvantage(Advantage, reasons)
+}
+
+// WithDisadvantage gives the roll disadvantage (roll twice, take lower), with
+// optional reasons shown in the detail. This is a D&D 5e mechanic.
+// See WithAdvantage for how sources combine.
+//
+// Example:
+//
+//	roller.Dice(1, 20).WithDisadvantage("poisoned").Roll()
+func (rb *RollBuilder) WithDisadvantage(reasons ...string) *RollBuilder {
+	return rb.addAdvantage(Disadvantage, reasons)
+}
+
+// addAdvantage records one source of the given type per reason, or a single
+// unnamed source if there are no reasons.
+func (rb *RollBuilder) addAdvantage(kind AdvantageType, reasons []string) *RollBuilder {
+	if len(reasons) == 0 {
+		reasons = []string{""}
+	}
+	for _, reason := range reasons {
+		rb.advantages = append(rb.advantages, AdvantageSource{Type: kind, Reason: strings.ToLower(reason)})
+	}
 	return rb
 }
 
-// WithDisadvantage sets the roll to use disadvantage (roll twice, take lower).
-// This is a D&D 5e mechanic.
+// WithAdvantagePolicy sets how the roll's advantage and disadvantage sources
+// are resolved, replacing the default of CancelAdvantage.
 //
 // Example:
 //
-//	roller.Dice(1, 20).WithDisadvantage().Roll()
-func (rb *RollBuilder) WithDisadvantage() *RollBuilder {
-	rb.advantageType = Disadvantage
+//	roller.Dice(1, 20).WithAdvantagePolicy(d20.NetAdvantage).
+//		WithAdvantage("flanking", "hidden").WithDisadvantage("poisoned").Roll() // Advantage
+func (rb *RollBuilder) WithAdvantagePolicy(policy AdvantagePolicy) *RollBuilder {
+	rb.advantagePolicy = policy
 	return rb
 }
 
-// Normal explicitly sets the roll to normal (no advantage/disadvantage).
+// Normal explicitly sets the roll to normal, clearing any advantage and
+// disadvantage sources added so far.
 // Usually not needed as Normal is the default, but provided for completeness.
 //
 // Example:
 //
 //	roller.Dice(1, 20).WithAdvantage().Normal().Roll() // Normal overrides advantage
 func (rb *RollBuilder) Normal() *RollBuilder {
-	rb.advantageType = Normal
+	rb.advantages = nil
+	return rb
+}
+
+// advantage resolves the roll's advantage sources with its policy.
+func (rb *RollBuilder) advantage() AdvantageType {
+	if rb.advantagePolicy == nil {
+		return CancelAdvantage(rb.advantages)
+	}
+	return rb.advantagePolicy(rb.advantages)
+}
+
+// KeepHighest keeps only the n highest dice, dropping the rest.
+// Equivalent to the "kh" notation suffix, e.g. "3d20kh1".
+//
+// Example:
+//
+//	roller.Dice(3, 20).KeepHighest(1).Roll() // Elven Accuracy
+func (rb *RollBuilder) KeepHighest(n uint) *RollBuilder {
+	rb.primary.keep = keepRule{mode: keepHighest, n: n}
+	return rb
+}
+
+// KeepLowest keeps only the n lowest dice, dropping the rest.
+// Equivalent to the "kl" notation suffix, e.g. "2d20kl1".
+func (rb *RollBuilder) KeepLowest(n uint) *RollBuilder {
+	rb.primary.keep = keepRule{mode: keepLowest, n: n}
+	return rb
+}
+
+// DropHighest drops the n highest dice.
+// Equivalent to the "dh" notation suffix, e.g. "4d6dh1".
+func (rb *RollBuilder) DropHighest(n uint) *RollBuilder {
+	rb.primary.keep = keepRule{mode: dropHighest, n: n}
+	return rb
+}
+
+// DropLowest drops the n lowest dice.
+// Equivalent to the "dl" notation suffix, e.g. "4d6dl1".
+//
+// Example:
+//
+//	roller.Dice(4, 6).DropLowest(1).Roll() // Ability score
+func (rb *RollBuilder) DropLowest(n uint) *RollBuilder {
+	rb.primary.keep = keepRule{mode: dropLowest, n: n}
+	return rb
+}
+
+// Explode makes dice that roll their maximum face (or the ExplodeOn condition)
+// roll again, adding each extra roll as a new die. Extra dice can explode too.
+// Equivalent to the "!" notation suffix, e.g. "1d6!".
+//
+// Example:
+//
+//	roller.Dice(1, 6).Explode().Roll() // Savage Worlds ace
+func (rb *RollBuilder) Explode() *RollBuilder {
+	rb.primary.explode.mode = explodeStandard
+	return rb
+}
+
+// Compound makes exploding dice add their extra rolls into the same die
+// instead of adding new dice. Equivalent to the "!!" notation suffix.
+func (rb *RollBuilder) Compound() *RollBuilder {
+	rb.primary.explode.mode = explodeCompound
+	return rb
+}
+
+// Penetrate makes exploding dice add new dice with 1 subtracted from each
+// extra roll, as in Hackmaster. Equivalent to the "!p" notation suffix.
+func (rb *RollBuilder) Penetrate() *RollBuilder {
+	rb.primary.explode.mode = explodePenetrate
+	return rb
+}
+
+// ExplodeOn sets which faces explode, replacing the default of the maximum face.
+// Enables standard explosions if no explode mode is set yet.
+// Equivalent to a condition after the explode suffix, e.g. "1d10!>=9".
+//
+// Example:
+//
+//	roller.Dice(1, 10).ExplodeOn(d20.AtLeast(9)).Roll()
+func (rb *RollBuilder) ExplodeOn(on Condition) *RollBuilder {
+	if rb.primary.explode.mode == explodeNone {
+		rb.primary.explode.mode = explodeStandard
+	}
+	rb.primary.explode.on = on
+	return rb
+}
+
+// ExplosionLimit caps how many extra rolls a single die may explode into,
+// so dice that always explode (such as a d1) still terminate.
+// Defaults to 100; passing zero restores the default.
+func (rb *RollBuilder) ExplosionLimit(n uint) *RollBuilder {
+	rb.primary.explode.limit = n
+	return rb
+}
+
+// Reroll rerolls any die matching the condition until it no longer matches,
+// recording the replaced values on the die. Equivalent to the "r" notation
+// suffix, e.g. "1d6r1".
+func (rb *RollBuilder) Reroll(on Condition) *RollBuilder {
+	rb.primary.reroll = rerollRule{on: on}
+	return rb
+}
+
+// RerollOnce rerolls any die matching the condition a single time, keeping the
+// new value even if it matches again. Equivalent to the "ro" notation suffix.
+//
+// Example:
+//
+//	roller.Dice(2, 6).RerollOnce(d20.AtMost(2)).Roll() // Great Weapon Fighting
+//	roller.Dice(1, 20).RerollOnce(d20.Equals(1)).Roll() // Halfling Lucky
+func (rb *RollBuilder) RerollOnce(on Condition) *RollBuilder {
+	rb.primary.reroll = rerollRule{on: on, once: true}
 	return rb
 }
 
@@ -187,55 +493,84 @@ func (rb *RollBuilder) Normal() *RollBuilder {
 //
 //	result, err  := roller.Dice(2, 6).WithModifier("strength", 3).Roll()
 func (rb *RollBuilder) Roll() (RollOutcome, error) {
-	if rb.rollCount == 0 {
-		return RollOutcome{}, errRollCountZero
+	outcome, _, err := rb.roll()
+	return outcome, err
+}
+
+// roll performs the roll, also returning the evaluation for callers that
+// need more than the RollOutcome.
+func (rb *RollBuilder) roll() (RollOutcome, *evaluation, error) {
+	if err := rb.validateDamage(); err != nil {
+		return RollOutcome{}, nil, err
 	}
-	if rb.dieFaces == 0 {
-		return RollOutcome{}, errDieFacesZero
+	advantage := rb.advantage()
+	expr, primary := rb.damageExpr()
+	ev := newEvaluation(rb.roller, primary, advantage)
+	rb.roller.mu.Lock()
+	diceTotal, err := expr.eval(ev)
+	var modifiers []Modifier
+	if err == nil {
+		modifiers, err = rb.roller.rollModifiers(rb.damageModifiers())
+	}
+	if sourceErr := rb.roller.sourceErr(); sourceErr != nil {
+		err = sourceErr
+	}
+	history := rb.roller.history
+	rb.roller.mu.Unlock()
+	if err != nil {
+		return RollOutcome{}, nil, err
 	}
 
-	// Roll the dice with advantage/disadvantage
-	var rolls []int
-	var diceTotal int
-
-	switch rb.advantageType {
-	case Normal:
-		// Roll normally - one roll per die
-		rolls = make([]int, rb.rollCount)
-		for i := range rb.rollCount {
-			rolls[i] = rb.roller.rng.Intn(int(rb.dieFaces)) + 1
-			diceTotal += rolls[i]
-		}
+	modifiers, suppressed := rb.stack(modifiers)
+	modifierTotal := 0
+	for _, mod := range modifiers {
+		modifierTotal += mod.Value
+	}
 
-	case Advantage:
-		// Roll twice per die, keep all rolls but use higher values for total
-		// For 1d20 with advantage: rolls = [17, 12], used 17
-		rolls = make([]int, rb.rollCount*2)
-		for i := range rb.rollCount {
-			roll1 := rb.roller.rng.Intn(int(rb.dieFaces)) + 1
-			roll2 := rb.roller.rng.Intn(int(rb.dieFaces)) + 1
-			rolls[i*2] = roll1
-			rolls[i*2+1] = roll2
-			diceTotal += max(roll1, roll2)
-		}
+	total, steps := rb.scaleDamage(expr, modifiers, diceTotal+modifierTotal)
 
-	case Disadvantage:
-		// Roll twice per die, keep all rolls but use lower values for total
-		// For 1d20 with disadvantage: rolls = [8, 14], used 8
-		rolls = make([]int, rb.rollCount*2)
-		for i := range rb.rollCount {
-			roll1 := rb.roller.rng.Intn(int(rb.dieFaces)) + 1
-			roll2 := rb.roller.rng.Intn(int(rb.dieFaces)) + 1
-			rolls[i*2] = roll1
-			rolls[i*2+1] = roll2
-			diceTotal += min(roll1, roll2)
-		}
+	// A lone dice term keeps the classic "Rolled 2d6... 4, 2" format
+	formatted := expr.render(ev)
+	if expr == primary {
+		formatted = formatDice(ev.dice)
+	}
+	var notes []string
+	if ev.pool != nil {
+		notes = ev.pool.notes()
+	}
+	if note := advantageNote(rb.advantages, advantage); note != "" {
+		notes = append(notes, note)
+	}
+	if len(suppressed) > 0 {
+		notes = append(notes, suppressedNote(suppressed))
+	}
+	natural, critical, fumble := ev.crits()
+	if critical {
+		notes = append(notes, "*Critical!*")
+	}
+	if fumble {
+		notes = append(notes, "*Fumble!*")
 	}
 
-	modifierTotal := 0
-	for _, mod := range rb.modifiers {
-		modifierTotal += mod.Value
+	outcome := newRollOutcome(expr.String(), formatted, ev.dice, modifiers, steps, total, notes...)
+	outcome.Terms = ev.terms
+	outcome.Suppressed = suppressed
+	outcome.Natural, outcome.IsCritical, outcome.IsFumble = natural, critical, fumble
+
+	if history != nil {
+		history.record(newHistoryEntry(rb.describe(), outcome, rb.actorID, rb.label))
 	}
+	return outcome, ev, nil
+}
 
-	return NewRollOutcome(rb.rollCount, rb.dieFaces, rolls, rb.modifiers, diceTotal+modifierTotal), nil
+// describe summarizes the roll for the history, e.g. "1d20 with advantage".
+func (rb *RollBuilder) describe() string {
+	expr, _ := rb.damageExpr()
+	switch rb.advantage() {
+	case Advantage:
+		return expr.String() + " with advantage"
+	case Disadvantage:
+		return expr.String() + " with disadvantage"
+	}
+	return expr.String()
 }
@@ -1,6 +1,10 @@
 package d20
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -9,8 +13,8 @@ func TestNewRoller(t *testing.T) {
 	if roller == nil {
 		t.Fatal("NewRoller returned nil")
 	}
-	if roller.rng == nil {
-		t.Fatal("Roller.rng is nil")
+	if roller.source == nil {
+		t.Fatal("Roller.source is nil")
 	}
 }
 
@@ -86,6 +90,165 @@ func TestRollBuilder_WithDisadvantage(t *testing.T) {
 	}
 }
 
+func TestRollBuilder_AdvantageSources(t *testing.T) {
+	tests := []struct {
+		name       string
+		builder    func(*Roller) *RollBuilder
+		wantValue  int
+		wantDice   int
+		wantNote   string
+		wantNoNote bool
+	}{
+		{
+			name:       "Unnamed advantage adds no note",
+			builder:    func(r *Roller) *RollBuilder { return r.Dice(1, 20).WithAdvantage() },
+			wantValue:  15,
+			wantDice:   2,
+			wantNoNote: true,
+		},
+		{
+			name:      "Named advantage",
+			builder:   func(r *Roller) *RollBuilder { return r.Dice(1, 20).WithAdvantage("Flanking", "hidden") },
+			wantValue: 15,
+			wantDice:  2,
+			wantNote:  "; Advantage (flanking, hidden)",
+		},
+		{
+			name: "Advantage and disadvantage cancel",
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 20).WithAdvantage("flanking", "hidden").WithDisadvantage("poisoned")
+			},
+			wantValue: 4,
+			wantDice:  1,
+			wantNote:  "; Advantage (flanking, hidden) and disadvantage (poisoned): normal",
+		},
+		{
+			name: "Net advantage policy",
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 20).WithAdvantagePolicy(NetAdvantage).
+					WithAdvantage("flanking", "hidden").WithDisadvantage("poisoned")
+			},
+			wantValue: 15,
+			wantDice:  2,
+			wantNote:  "; Advantage (flanking, hidden) and disadvantage (poisoned): advantage",
+		},
+		{
+			name: "Disadvantage stacks",
+			builder: func(r *Roller) *RollBuilder {
+				return r.Dice(1, 20).WithDisadvantage("prone").WithDisadvantage("blinded")
+			},
+			wantValue: 4,
+			wantDice:  2,
+			wantNote:  "; Disadvantage (prone, blinded)",
+		},
+		{
+			name:       "Normal clears sources",
+			builder:    func(r *Roller) *RollBuilder { return r.Dice(1, 20).WithAdvantage("flanking").Normal() },
+			wantValue:  4,
+			wantDice:   1,
+			wantNoNote: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roller := NewScriptedRoller(NewScript(4, 15))
+			result, err := tt.builder(roller).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			if result.Value != tt.wantValue || len(result.Dice) != tt.wantDice {
+				t.Errorf("Value = %d with %d dice, want %d with %d", result.Value, len(result.Dice), tt.wantValue, tt.wantDice)
+			}
+			if tt.wantNoNote && strings.Contains(strings.ToLower(result.Detail), "advantage") {
+				t.Errorf("Detail %q should not describe advantage", result.Detail)
+			}
+			if !tt.wantNoNote && !strings.HasSuffix(result.Detail, tt.wantNote) {
+				t.Errorf("Detail %q should end with %q", result.Detail, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestRollBuilder_WithDiceModifier(t *testing.T) {
+	t.Run("Rolled into the result and detail", func(t *testing.T) {
+		roller := NewScriptedRoller(NewScript(12, 3))
+		result, err := roller.Dice(1, 20).
+			WithModifier("strength", 3).
+			WithDiceModifier("Bless", "1d4").
+			Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Value != 18 {
+			t.Errorf("Value = %d, want 18", result.Value)
+		}
+		want := "Rolled 1d20... 12; +3 strength, +1d4 (3) bless; *Result: 18*"
+		if result.Detail != want {
+			t.Errorf("Detail = %q, want %q", result.Detail, want)
+		}
+		if got := result.Modifiers[1]; got != (Modifier{Value: 3, Reason: "bless", Dice: "1d4"}) {
+			t.Errorf("Modifiers[1] = %+v, want the rolled bless modifier", got)
+		}
+		if len(result.Dice) != 1 {
+			t.Errorf("modifier dice should not be listed with the roll's dice, got %d dice", len(result.Dice))
+		}
+	})
+
+	t.Run("Negative dice", func(t *testing.T) {
+		roller := NewScriptedRoller(NewScript(12, 2))
+		result, err := roller.Dice(1, 20).WithDiceModifier("bane", "-1d4").Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Value != 10 || !strings.Contains(result.Detail, "; -1d4 (-2) bane;") {
+			t.Errorf("Value = %d, Detail = %q; want 10 with the bane penalty", result.Value, result.Detail)
+		}
+	})
+
+	t.Run("Rerolled every roll", func(t *testing.T) {
+		roller := NewScriptedRoller(NewScript(10, 1, 10, 4))
+		builder := roller.Dice(1, 20).WithDiceModifier("bless", "1d4")
+		first, _ := builder.Roll()
+		second, _ := builder.Roll()
+		if first.Value != 11 || second.Value != 14 {
+			t.Errorf("Values = %d, %d; want 11, 14", first.Value, second.Value)
+		}
+	})
+
+	t.Run("Uppercase notation", func(t *testing.T) {
+		mod, err := NewDiceModifier("bless", " 1D4 ")
+		if err != nil {
+			t.Fatalf("NewDiceModifier() error: %v", err)
+		}
+		if mod.Dice != "1d4" {
+			t.Errorf("Dice = %q, want %q", mod.Dice, "1d4")
+		}
+
+		roller := NewScriptedRoller(NewScript(10, 3, 1))
+		result, err := roller.Dice(1, 20).
+			WithDiceModifier("bless", "1D4").
+			WithDiceModifier("fate", "1dF").
+			Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Value != 12 {
+			t.Errorf("Value = %d, want 12", result.Value)
+		}
+	})
+
+	t.Run("Invalid notation", func(t *testing.T) {
+		roller := NewRoller(42)
+		if _, err := roller.Dice(1, 20).WithDiceModifier("bless", "1d").Roll(); err == nil {
+			t.Error("Roll() should report invalid modifier notation")
+		}
+		if _, err := NewDiceModifier("bless", "bless"); err == nil {
+			t.Error("NewDiceModifier() should reject invalid notation")
+		}
+	})
+}
+
 func TestRollBuilder_InvalidInput(t *testing.T) {
 	roller := NewRoller(42)
 
@@ -124,7 +287,9 @@ func TestRoller_Roll_DiceNotation(t *testing.T) {
 		{"Invalid - no d", "20", true, 0, 0},
 		{"Invalid - no faces", "2d", true, 0, 0},
 		{"Invalid - letter faces", "1dabc", true, 0, 0},
-		{"Invalid - multiple modifiers", "1d20+3+2", true, 0, 0},
+		{"Multiple modifiers", "1d20+3+2", false, 6, 25},
+		{"Multiple dice terms", "2d6+1d4+3", false, 6, 19},
+		{"Invalid - no dice", "3+2", true, 0, 0},
 		{"Invalid - empty", "", true, 0, 0},
 	}
 
@@ -208,3 +373,554 @@ func TestRoller_Roll_Values(t *testing.T) {
 		}
 	})
 }
+
+func TestRollBuilder_KeepDrop(t *testing.T) {
+	roller := NewRoller(42)
+
+	tests := []struct {
+		name      string
+		builder   func() *RollBuilder
+		wantKept  int
+		wantTotal func(sorted []int) int // sorted descending
+	}{
+		{"Keep highest", func() *RollBuilder { return roller.Dice(4, 6).KeepHighest(3) }, 3,
+			func(s []int) int { return s[0] + s[1] + s[2] }},
+		{"Keep lowest", func() *RollBuilder { return roller.Dice(4, 6).KeepLowest(1) }, 1,
+			func(s []int) int { return s[3] }},
+		{"Drop highest", func() *RollBuilder { return roller.Dice(4, 6).DropHighest(1) }, 3,
+			func(s []int) int { return s[1] + s[2] + s[3] }},
+		{"Drop lowest", func() *RollBuilder { return roller.Dice(4, 6).DropLowest(1) }, 3,
+			func(s []int) int { return s[0] + s[1] + s[2] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				result, err := tt.builder().Roll()
+				if err != nil {
+					t.Fatalf("Roll() error: %v", err)
+				}
+				if len(result.DiceRolls) != 4 || len(result.Dice) != 4 {
+					t.Fatalf("expected 4 dice, got %d rolls and %d dice", len(result.DiceRolls), len(result.Dice))
+				}
+				if kept := result.Kept(); len(kept) != tt.wantKept {
+					t.Fatalf("expected %d kept dice, got %v", tt.wantKept, kept)
+				}
+
+				sorted := append([]int(nil), result.DiceRolls...)
+				slices.Sort(sorted)
+				slices.Reverse(sorted)
+				if want := tt.wantTotal(sorted); result.Value != want {
+					t.Errorf("expected value %d from %v, got %d", want, result.DiceRolls, result.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestRollBuilder_KeepDropInvalid(t *testing.T) {
+	roller := NewRoller(42)
+
+	if _, err := roller.Dice(2, 20).KeepHighest(3).Roll(); err == nil {
+		t.Error("expected error keeping more dice than rolled")
+	}
+	if _, err := roller.Dice(2, 20).DropLowest(2).Roll(); err == nil {
+		t.Error("expected error dropping every die")
+	}
+	if _, err := roller.Dice(2, 20).KeepLowest(0).Roll(); err == nil {
+		t.Error("expected error keeping zero dice")
+	}
+}
+
+func TestRollBuilder_AdvantageMarksDropped(t *testing.T) {
+	roller := NewRoller(42)
+	result, err := roller.Dice(1, 20).WithAdvantage().Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+
+	kept := result.Kept()
+	if len(kept) != 1 || kept[0] != result.Value {
+		t.Errorf("expected single kept die equal to value %d, got %v", result.Value, kept)
+	}
+	if !result.Dice[0].Dropped && !result.Dice[1].Dropped {
+		t.Error("expected one die to be marked dropped")
+	}
+}
+
+func TestRollBuilder_Explode(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Explosion limit stops endless explosions", func(t *testing.T) {
+		result, err := roller.Dice(1, 1).Explode().ExplosionLimit(5).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.DiceRolls) != 6 {
+			t.Fatalf("expected 6 dice (1 + 5 explosions), got %v", result.DiceRolls)
+		}
+		if result.Value != 6 {
+			t.Errorf("expected value 6, got %d", result.Value)
+		}
+		if result.Dice[0].Exploded {
+			t.Error("original die should not be marked exploded")
+		}
+		for i, die := range result.Dice[1:] {
+			if !die.Exploded {
+				t.Errorf("extra die %d should be marked exploded", i+1)
+			}
+		}
+	})
+
+	t.Run("Default limit", func(t *testing.T) {
+		result, err := roller.Dice(1, 1).Explode().Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.DiceRolls) != defaultExplosionLimit+1 {
+			t.Errorf("expected %d dice, got %d", defaultExplosionLimit+1, len(result.DiceRolls))
+		}
+	})
+
+	t.Run("Compound adds into one die", func(t *testing.T) {
+		result, err := roller.Dice(1, 1).Compound().ExplosionLimit(3).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.Dice) != 1 {
+			t.Fatalf("expected 1 compounded die, got %d", len(result.Dice))
+		}
+		if result.Value != 4 || result.Dice[0].Compounded != 3 {
+			t.Errorf("expected value 4 with 3 compounded rolls, got %+v", result.Dice[0])
+		}
+		if !strings.Contains(result.Detail, "4!!") {
+			t.Errorf("expected compounded die marked in detail, got %q", result.Detail)
+		}
+	})
+
+	t.Run("Penetrate subtracts one from extra dice", func(t *testing.T) {
+		result, err := roller.Dice(1, 1).Penetrate().ExplosionLimit(3).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.Dice) != 4 {
+			t.Fatalf("expected 4 dice, got %d", len(result.Dice))
+		}
+		if result.Value != 1 {
+			t.Errorf("expected value 1 (1 + 0 + 0 + 0), got %d", result.Value)
+		}
+	})
+
+	t.Run("Explode on threshold", func(t *testing.T) {
+		for range 50 {
+			result, err := roller.Dice(1, 6).ExplodeOn(AtLeast(5)).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			for i, die := range result.Dice {
+				last := i == len(result.Dice)-1
+				if last && die.Value >= 5 {
+					t.Errorf("last die %d should not have exploded: %v", die.Value, result.DiceRolls)
+				}
+				if !last && die.Value < 5 {
+					t.Errorf("die %d should not have exploded: %v", die.Value, result.DiceRolls)
+				}
+			}
+		}
+	})
+
+	t.Run("Notation", func(t *testing.T) {
+		result, err := roller.Roll("1d1!p>=1")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.Dice) != defaultExplosionLimit+1 {
+			t.Errorf("expected %d dice, got %d", defaultExplosionLimit+1, len(result.Dice))
+		}
+	})
+}
+
+func TestRollBuilder_Reroll(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Reroll once keeps the second value", func(t *testing.T) {
+		result, err := roller.Dice(1, 1).RerollOnce(Equals(1)).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		die := result.Dice[0]
+		if die.Value != 1 || !slices.Equal(die.Rerolled, []int{1}) {
+			t.Errorf("expected value 1 rerolled from [1], got %+v", die)
+		}
+		if !strings.Contains(result.Detail, "1 (rerolled 1)") {
+			t.Errorf("expected reroll in detail, got %q", result.Detail)
+		}
+	})
+
+	t.Run("Reroll once only rerolls matching dice", func(t *testing.T) {
+		for range 50 {
+			result, err := roller.Dice(2, 6).RerollOnce(AtMost(2)).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			for _, die := range result.Dice {
+				for _, original := range die.Rerolled {
+					if original > 2 {
+						t.Errorf("die showing %d should not have been rerolled", original)
+					}
+				}
+				if len(die.Rerolled) > 1 {
+					t.Errorf("die rerolled %d times, expected at most once", len(die.Rerolled))
+				}
+			}
+			if result.Value != result.DiceRolls[0]+result.DiceRolls[1] {
+				t.Errorf("value %d should use replacement values %v", result.Value, result.DiceRolls)
+			}
+		}
+	})
+
+	t.Run("Reroll until the condition no longer matches", func(t *testing.T) {
+		for range 50 {
+			result, err := roller.Dice(1, 6).Reroll(AtMost(3)).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			if result.Value <= 3 {
+				t.Errorf("expected final value above 3, got %d", result.Value)
+			}
+		}
+	})
+
+	t.Run("Reroll until is capped", func(t *testing.T) {
+		result, err := roller.Roll("1d1r1")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if len(result.Dice[0].Rerolled) != defaultRerollLimit {
+			t.Errorf("expected %d rerolls, got %d", defaultRerollLimit, len(result.Dice[0].Rerolled))
+		}
+	})
+}
+
+func TestRoller_DieKinds(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Fate dice show -1, 0 or +1", func(t *testing.T) {
+		for range 50 {
+			result, err := roller.Roll("4dF")
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			total := 0
+			for _, die := range result.Dice {
+				if die.Kind != FateDie || die.Value < -1 || die.Value > 1 {
+					t.Fatalf("unexpected Fate die %+v", die)
+				}
+				total += die.Value
+			}
+			if result.Value != total {
+				t.Errorf("expected value %d, got %d", total, result.Value)
+			}
+		}
+	})
+
+	t.Run("Fate dice render as symbols", func(t *testing.T) {
+		result, err := roller.Fate(4).WithModifier("careful", 2).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if !strings.HasPrefix(result.Detail, "Rolled 4dF... [") {
+			t.Errorf("unexpected detail prefix: %q", result.Detail)
+		}
+		for _, digit := range []string{"-1", "0,", "1,"} {
+			if strings.Contains(result.Detail, digit) {
+				t.Errorf("detail should show symbols instead of %q: %q", digit, result.Detail)
+			}
+		}
+	})
+
+	t.Run("Percentile alias", func(t *testing.T) {
+		result, err := roller.Roll("d%")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Dice[0].Faces != 100 || result.Value < 1 || result.Value > 100 {
+			t.Errorf("unexpected d%% result %+v", result.Dice[0])
+		}
+	})
+
+	t.Run("d66 reads tens and ones", func(t *testing.T) {
+		for range 50 {
+			result, err := roller.D66(1).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			tens, ones := result.Value/10, result.Value%10
+			if tens < 1 || tens > 6 || ones < 1 || ones > 6 {
+				t.Errorf("d66 rolled invalid value %d", result.Value)
+			}
+		}
+	})
+
+	t.Run("d66 explodes on 66", func(t *testing.T) {
+		builder, err := roller.Notation("1d66!")
+		if err != nil {
+			t.Fatalf("Notation() error: %v", err)
+		}
+		if on := builder.primary.explode.condition(builder.primary.maxFace()); !on.Matches(66) || on.Matches(36) {
+			t.Errorf("d66 should explode on 66, got %s", on)
+		}
+	})
+}
+
+func TestRollOutcome_Breakdown(t *testing.T) {
+	roller := NewRoller(42)
+
+	t.Run("Dice record their term", func(t *testing.T) {
+		builder, err := roller.Notation("1d20+1d4+5")
+		if err != nil {
+			t.Fatalf("Notation() error: %v", err)
+		}
+		result, err := builder.WithAdvantage().WithModifier("strength", 3).Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+
+		wantTerms := []string{"1d20", "1d4"}
+		if len(result.Terms) != len(wantTerms) {
+			t.Fatalf("expected %d terms, got %+v", len(wantTerms), result.Terms)
+		}
+		for i, want := range wantTerms {
+			if result.Terms[i].Notation != want {
+				t.Errorf("term %d notation = %q, want %q", i, result.Terms[i].Notation, want)
+			}
+		}
+
+		wantDieTerms := []int{0, 0, 1}
+		totals := make([]int, len(result.Terms))
+		for i, die := range result.Dice {
+			if die.Term != wantDieTerms[i] {
+				t.Errorf("die %d term = %d, want %d", i, die.Term, wantDieTerms[i])
+			}
+			if die.Raw != die.Value {
+				t.Errorf("die %d raw = %d, want %d", i, die.Raw, die.Value)
+			}
+			if !die.Dropped {
+				totals[die.Term] += die.Value
+			}
+		}
+		for i, term := range result.Terms {
+			if term.Value != totals[i] {
+				t.Errorf("term %d value = %d, want %d", i, term.Value, totals[i])
+			}
+		}
+
+		wantMods := []Modifier{NewModifier("modifier", 5), NewModifier("strength", 3)}
+		if !slices.Equal(result.Modifiers, wantMods) {
+			t.Errorf("modifiers = %v, want %v", result.Modifiers, wantMods)
+		}
+	})
+
+	t.Run("Raw face of penetrating dice", func(t *testing.T) {
+		result, err := roller.Roll("1d1!p")
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		for _, die := range result.Dice[1:] {
+			if die.Raw != 1 || die.Value != 0 {
+				t.Errorf("penetrating die raw = %d value = %d, want raw 1 value 0", die.Raw, die.Value)
+			}
+		}
+	})
+
+	t.Run("Modifiers are not shared with the builder", func(t *testing.T) {
+		builder := roller.Dice(1, 20).WithModifier("strength", 3)
+		result, err := builder.Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		builder.WithModifier("bless", 2)
+		if len(result.Modifiers) != 1 {
+			t.Errorf("outcome modifiers changed after the roll: %v", result.Modifiers)
+		}
+	})
+}
+
+func TestRoller_ConcurrentRolls(t *testing.T) {
+	roller := NewRoller(42)
+	actor, err := NewActor("hero").WithHP(20).WithAttribute("stealth", 45).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	const goroutines, rolls = 16, 200
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rolls {
+				result, err := roller.Roll("4d6kh3+1d4!")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if result.Value < 4 {
+					errs <- fmt.Errorf("impossible result %d", result.Value)
+					return
+				}
+				if _, _, err := actor.D100SkillCheck("stealth", roller, 1); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestRoller_Fork(t *testing.T) {
+	// rollForks forks one stream per worker, rolls on every stream
+	// concurrently, and returns each worker's rolls
+	rollForks := func(seed int64) [][]int {
+		parent := NewRoller(seed)
+		results := make([][]int, 8)
+		var wg sync.WaitGroup
+		for i := range results {
+			fork := parent.Fork()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					result, err := fork.Dice(1, 20).Roll()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					results[i] = append(results[i], result.Value)
+				}
+			}()
+		}
+		wg.Wait()
+		return results
+	}
+
+	first, second := rollForks(42), rollForks(42)
+	for i := range first {
+		if !slices.Equal(first[i], second[i]) {
+			t.Errorf("fork %d is not reproducible from the seed", i)
+		}
+	}
+	if slices.Equal(first[0], first[1]) {
+		t.Error("forks should roll independent streams")
+	}
+}
+
+func TestRoller_Derive(t *testing.T) {
+	rolls := func(roller *Roller) []int {
+		var values []int
+		for range 20 {
+			result, err := roller.Dice(1, 20).Roll()
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			values = append(values, result.Value)
+		}
+		return values
+	}
+
+	t.Run("Independent of earlier rolls and derivations", func(t *testing.T) {
+		first := NewRoller(42)
+		want := rolls(first.Derive("goblin"))
+
+		second := NewRoller(42)
+		rolls(second)
+		second.Derive("loot")
+		second.Fork()
+		if got := rolls(second.Derive("goblin")); !slices.Equal(got, want) {
+			t.Error("derived stream should depend only on the seed and label")
+		}
+	})
+
+	t.Run("Labels and seeds give different streams", func(t *testing.T) {
+		roller := NewRoller(42)
+		goblin := rolls(roller.Derive("goblin"))
+		if slices.Equal(goblin, rolls(roller.Derive("orc"))) {
+			t.Error("different labels should derive different streams")
+		}
+		if slices.Equal(goblin, rolls(NewRoller(43).Derive("goblin"))) {
+			t.Error("different seeds should derive different streams")
+		}
+	})
+
+	t.Run("Nested derivation", func(t *testing.T) {
+		a := rolls(NewRoller(42).Derive("combat").Derive("goblin"))
+		b := rolls(NewRoller(42).Derive("combat").Derive("goblin"))
+		if !slices.Equal(a, b) {
+			t.Error("nested derivations should be reproducible")
+		}
+	})
+
+	t.Run("Survives snapshot restore", func(t *testing.T) {
+		for name, roller := range map[string]*Roller{
+			"Seeded":  NewRoller(42),
+			"PCG":     NewRollerWithSource(NewPCGSource(1, 2)),
+			"ChaCha8": NewRollerWithSource(NewChaCha8Source([32]byte{1})),
+		} {
+			rolls(roller)
+			state, err := roller.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s: MarshalBinary() error: %v", name, err)
+			}
+			var restored Roller
+			if err := restored.UnmarshalBinary(state); err != nil {
+				t.Fatalf("%s: UnmarshalBinary() error: %v", name, err)
+			}
+			if !slices.Equal(rolls(roller.Derive("loot")), rolls(restored.Derive("loot"))) {
+				t.Errorf("%s: restored roller should derive the same streams", name)
+			}
+		}
+	})
+
+	t.Run("Sources without a seed", func(t *testing.T) {
+		roller := NewRollerWithSource(NewCryptoSource())
+		if !slices.Equal(rolls(roller.Derive("goblin")), rolls(roller.Derive("goblin"))) {
+			t.Error("the same label should derive the same stream")
+		}
+	})
+
+	t.Run("Scripted values are kept", func(t *testing.T) {
+		script := NewScript(20, 1, 5)
+		roller := NewScriptedRoller(script)
+		goblin := roller.Derive("goblin")
+		if script.Remaining() != 3 {
+			t.Fatalf("Derive() used scripted values: Remaining() = %d, want 3", script.Remaining())
+		}
+		for _, want := range []int{20, 1, 5} {
+			result, err := roller.Roll("1d20")
+			if err != nil || result.Value != want {
+				t.Errorf("expected scripted %d after Derive(), got %d (%v)", want, result.Value, err)
+			}
+		}
+
+		other := NewScriptedRoller(NewScript())
+		other.Fork()
+		if !slices.Equal(rolls(goblin), rolls(other.Derive("goblin"))) {
+			t.Error("scripted rollers should derive the same stream for a label")
+		}
+	})
+
+	t.Run("Shares history", func(t *testing.T) {
+		history := NewHistory(0)
+		roller := NewRoller(42).WithHistory(history)
+		rolls(roller.Derive("goblin"))
+		if history.Len() != 20 {
+			t.Errorf("history recorded %d rolls, want 20", history.Len())
+		}
+	})
+}
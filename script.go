@@ -0,0 +1,147 @@
+package d20
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	errScriptExhausted  = errors.New("scripted rolls exhausted")
+	errScriptOutOfRange = errors.New("scripted roll out of range for die")
+)
+
+// Script is a RandomSource that returns predefined die results instead of
+// random ones, so tests can say "the player rolls a natural 20, then a 1".
+// Results are queued per die size with ForDie or globally with NewScript;
+// a die uses its own size's queue first and falls back to the global queue.
+//
+// Rolling from an exhausted Script, or a scripted value that the die can't
+// show, makes the roll return an error.
+//
+// Every value is a face from 1 to the die's size. Fate dice draw as a d3
+// (1 for minus, 2 for blank, 3 for plus) and d66 draws two d6.
+// D100SkillCheck draws a d10 for each tens digit and then one for the ones
+// digit, each reading a scripted value v as the digit v-1; Percentile queues
+// those draws for a given result.
+type Script struct {
+	mu       sync.Mutex
+	global   []int
+	bySize   map[int][]int
+	consumed []ScriptedRoll
+	err      error // First failure since the last roll, reported by takeErr
+}
+
+// ScriptedRoll records a scripted value consumed by a die.
+type ScriptedRoll struct {
+	Faces int // Size of the die that consumed the value
+	Value int // Face the die showed
+}
+
+// NewScript creates a Script with a global queue of die results used by dice
+// of any size.
+//
+// Example:
+//
+//	script := d20.NewScript(20, 1)
+//	roller := d20.NewScriptedRoller(script)
+//	hit, _ := actor.AttackRoll(roller).Roll()  // Natural 20
+//	miss, _ := actor.AttackRoll(roller).Roll() // Natural 1
+func NewScript(values ...int) *Script {
+	return &Script{global: values, bySize: make(map[int][]int)}
+}
+
+// NewScriptedRoller creates a Roller that rolls the results queued in script.
+func NewScriptedRoller(script *Script) *Roller {
+	return NewRollerWithSource(script)
+}
+
+// ForDie queues results for dice with the given number of faces only.
+//
+// Example:
+//
+//	script := d20.NewScript().ForDie(20, 15).ForDie(8, 3, 6)
+func (s *Script) ForDie(faces int, values ...int) *Script {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bySize[faces] = append(s.bySize[faces], values...)
+	return s
+}
+
+// Percentile queues the d10 draws D100SkillCheck makes for each result,
+// from 1 to 100, without bonus or penalty dice.
+//
+// Example:
+//
+//	script := d20.NewScript().Percentile(5, 100) // An extreme success, then a fumble
+func (s *Script) Percentile(results ...int) *Script {
+	for _, result := range results {
+		s.ForDie(10, (result%100)/10+1, result%10+1)
+	}
+	return s
+}
+
+// Consumed returns the scripted values rolled so far, in order.
+func (s *Script) Consumed() []ScriptedRoll {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ScriptedRoll(nil), s.consumed...)
+}
+
+// Remaining returns how many scripted values have not been rolled yet.
+func (s *Script) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remaining := len(s.global)
+	for _, queue := range s.bySize {
+		remaining += len(queue)
+	}
+	return remaining
+}
+
+// IntN returns the next scripted value for a die of size n, minus one.
+// If none is available, it records an error for the roll and returns 0.
+func (s *Script) IntN(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var value int
+	switch {
+	case len(s.bySize[n]) > 0:
+		value, s.bySize[n] = s.bySize[n][0], s.bySize[n][1:]
+	case len(s.global) > 0:
+		value, s.global = s.global[0], s.global[1:]
+	default:
+		s.fail(fmt.Errorf("%w: no value left for a d%d", errScriptExhausted, n))
+		return 0
+	}
+
+	if value < 1 || value > n {
+		s.fail(fmt.Errorf("%w: %d on a d%d", errScriptOutOfRange, value, n))
+		return 0
+	}
+	s.consumed = append(s.consumed, ScriptedRoll{Faces: n, Value: value})
+	return value - 1
+}
+
+// fail records the first error of a roll. Must be called with s.mu held.
+func (s *Script) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// takeErr returns and clears the error recorded during the last roll.
+func (s *Script) takeErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// failingSource is implemented by random sources that can fail, such as
+// Script. Rollers check it after every roll.
+type failingSource interface {
+	takeErr() error
+}
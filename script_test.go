@@ -0,0 +1,153 @@
+package d20
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestScript_AttackRolls(t *testing.T) {
+	actor, err := NewActor("fighter").WithHP(20).WithCombatModifier("strength", 3).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	roller := NewScriptedRoller(NewScript(20, 1))
+
+	hit, err := actor.AttackRoll(roller).Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if hit.Natural != 20 || !hit.IsCritical || hit.Value != 23 {
+		t.Errorf("expected a natural 20 crit for 23, got %+v", hit)
+	}
+
+	miss, err := actor.AttackRoll(roller).Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if miss.Natural != 1 || !miss.IsFumble {
+		t.Errorf("expected a natural 1 fumble, got %+v", miss)
+	}
+}
+
+func TestScript_Queues(t *testing.T) {
+	script := NewScript(4, 5).ForDie(20, 17).ForDie(8, 8)
+	roller := NewScriptedRoller(script)
+
+	result, err := roller.Roll("1d20+1d8+1d6+1d6")
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	// The d20 and d8 use their own queues; the d6s fall back to the global queue
+	if want := []int{17, 8, 4, 5}; !slices.Equal(result.DiceRolls, want) {
+		t.Errorf("DiceRolls = %v, want %v", result.DiceRolls, want)
+	}
+
+	wantConsumed := []ScriptedRoll{{20, 17}, {8, 8}, {6, 4}, {6, 5}}
+	if got := script.Consumed(); !slices.Equal(got, wantConsumed) {
+		t.Errorf("Consumed() = %v, want %v", got, wantConsumed)
+	}
+	if script.Remaining() != 0 {
+		t.Errorf("Remaining() = %d, want 0", script.Remaining())
+	}
+}
+
+func TestScript_Errors(t *testing.T) {
+	t.Run("Exhausted", func(t *testing.T) {
+		roller := NewScriptedRoller(NewScript(6))
+		if _, err := roller.Roll("2d6"); !errors.Is(err, errScriptExhausted) {
+			t.Errorf("expected exhausted error, got %v", err)
+		}
+	})
+
+	t.Run("Out of range", func(t *testing.T) {
+		roller := NewScriptedRoller(NewScript(7))
+		if _, err := roller.Roll("1d6"); !errors.Is(err, errScriptOutOfRange) {
+			t.Errorf("expected out of range error, got %v", err)
+		}
+	})
+
+	t.Run("Refilled script rolls again", func(t *testing.T) {
+		script := NewScript()
+		roller := NewScriptedRoller(script)
+		if _, err := roller.Roll("1d20"); err == nil {
+			t.Fatal("expected an error from an empty script")
+		}
+		script.ForDie(20, 12)
+		result, err := roller.Roll("1d20")
+		if err != nil || result.Value != 12 {
+			t.Errorf("expected 12 after refilling, got %d (%v)", result.Value, err)
+		}
+	})
+
+	t.Run("D100SkillCheck", func(t *testing.T) {
+		actor, _ := NewActor("investigator").WithHP(10).WithAttribute("stealth", 45).Build()
+		if _, _, err := actor.D100SkillCheck("stealth", NewScriptedRoller(NewScript()), 0); !errors.Is(err, errScriptExhausted) {
+			t.Errorf("expected exhausted error, got %v", err)
+		}
+	})
+}
+
+func TestScript_Percentile(t *testing.T) {
+	actor, err := NewActor("investigator").WithHP(10).WithAttribute("stealth", 45).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	roller := NewScriptedRoller(NewScript().Percentile(5, 45, 46, 100))
+
+	for _, want := range []struct {
+		value   int
+		success bool
+	}{{5, true}, {45, true}, {46, false}, {100, false}} {
+		success, outcome, err := actor.D100SkillCheck("stealth", roller, 0)
+		if err != nil {
+			t.Fatalf("D100SkillCheck() error: %v", err)
+		}
+		if outcome.Value != want.value || success != want.success {
+			t.Errorf("rolled %d (success %v), want %d (success %v)", outcome.Value, success, want.value, want.success)
+		}
+	}
+}
+
+func TestScript_SkillCheck(t *testing.T) {
+	actor, err := NewActor("rogue").WithHP(10).WithAttribute("stealth", 7).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	builder, err := actor.SkillCheck("stealth", NewScriptedRoller(NewScript(3, 18)))
+	if err != nil {
+		t.Fatalf("SkillCheck() error: %v", err)
+	}
+
+	result, err := builder.WithAdvantage().Roll()
+	if err != nil {
+		t.Fatalf("Roll() error: %v", err)
+	}
+	if result.Value != 25 || result.Natural != 18 {
+		t.Errorf("expected 18 + 7 = 25 with advantage, got %+v", result)
+	}
+}
+
+func TestScript_Fork(t *testing.T) {
+	script := NewScript().ForDie(20, 20)
+	roller := NewScriptedRoller(script)
+
+	fork := roller.Fork()
+	if script.Remaining() != 1 {
+		t.Fatalf("Fork() used scripted values: Remaining() = %d, want 1", script.Remaining())
+	}
+	result, err := roller.Roll("1d20")
+	if err != nil || result.Value != 20 {
+		t.Errorf("expected the scripted 20 after Fork(), got %d (%v)", result.Value, err)
+	}
+
+	// The fork rolls from a fixed seed, so scripted tests stay reproducible
+	again := NewScriptedRoller(NewScript()).Fork()
+	for range 10 {
+		a, errA := fork.Roll("1d20")
+		b, errB := again.Roll("1d20")
+		if errA != nil || errB != nil || a.Value != b.Value {
+			t.Fatalf("forks of scripted rollers differ: %d (%v) and %d (%v)", a.Value, errA, b.Value, errB)
+		}
+	}
+}
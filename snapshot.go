@@ -0,0 +1,124 @@
+package d20
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	randv2 "math/rand/v2"
+)
+
+var (
+	errSnapshotUnsupported = errors.New("random source does not support snapshots")
+	errInvalidSnapshot     = errors.New("invalid roller snapshot")
+)
+
+// snapshotVersion is the format version written as the first snapshot byte.
+const snapshotVersion = 1
+
+// maxSeededDraws caps the stream position of a NewSeededSource snapshot.
+// Restoring replays every draw, so the cap keeps a corrupt or hostile
+// snapshot from tying up UnmarshalBinary; it allows a few hundred million
+// dice, and longer sessions should use NewPCGSource or NewChaCha8Source.
+const maxSeededDraws = 1 << 28
+
+// snapshotKind identifies the random source a snapshot restores.
+type snapshotKind byte
+
+const (
+	snapshotSeeded  snapshotKind = iota + 1 // NewSeededSource: seed and draw count
+	snapshotPCG                             // NewPCGSource: derivation key and PCG state
+	snapshotChaCha8                         // NewChaCha8Source: derivation key and ChaCha8 state
+)
+
+// MarshalBinary captures the Roller's random source, including its seed and
+// stream position, so a saved game can be reloaded to roll the same dice.
+// Implements encoding.BinaryMarshaler.
+//
+// Sources created by NewSeededSource, NewPCGSource and NewChaCha8Source
+// (and so NewRoller, NewRandomRoller and Fork) are supported. Returns an
+// error for other sources, such as NewCryptoSource or a Script, and for a
+// NewSeededSource that has drawn more than about 268 million values, since
+// restoring it replays every draw.
+//
+// Example:
+//
+//	state, _ := roller.MarshalBinary()
+//	// ... save state with the game, then later
+//	var restored d20.Roller
+//	_ = restored.UnmarshalBinary(state)
+func (r *Roller) MarshalBinary() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := []byte{snapshotVersion}
+	switch source := r.source.(type) {
+	case *seededSource:
+		if source.draws > maxSeededDraws {
+			return nil, fmt.Errorf("%w: seeded source is %d draws in, past the %d that can be restored", errSnapshotUnsupported, source.draws, maxSeededDraws)
+		}
+		data = append(data, byte(snapshotSeeded))
+		data = binary.BigEndian.AppendUint64(data, uint64(source.seed))
+		return binary.BigEndian.AppendUint64(data, source.draws), nil
+
+	case *stateRand:
+		kind := snapshotPCG
+		if _, ok := source.src.(*randv2.ChaCha8); ok {
+			kind = snapshotChaCha8
+		}
+		state, err := source.src.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, byte(kind))
+		data = append(data, source.key[:]...)
+		return append(data, state...), nil
+	}
+	return nil, fmt.Errorf("%w: %T", errSnapshotUnsupported, r.source)
+}
+
+// UnmarshalBinary restores a random source captured by MarshalBinary,
+// replacing the Roller's current source. Later rolls match the ones the
+// original Roller would have made. Implements encoding.BinaryUnmarshaler.
+func (r *Roller) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != snapshotVersion {
+		return errInvalidSnapshot
+	}
+	kind, payload := snapshotKind(data[1]), data[2:]
+
+	var source RandomSource
+	switch kind {
+	case snapshotSeeded:
+		if len(payload) != 16 {
+			return errInvalidSnapshot
+		}
+		seed, draws := int64(binary.BigEndian.Uint64(payload)), binary.BigEndian.Uint64(payload[8:])
+		if draws > maxSeededDraws {
+			return fmt.Errorf("%w: %d draws is past the limit of %d", errInvalidSnapshot, draws, maxSeededDraws)
+		}
+		source = newSeededSource(seed, draws)
+
+	case snapshotPCG, snapshotChaCha8:
+		var key [32]byte
+		if len(payload) < len(key) {
+			return errInvalidSnapshot
+		}
+		copy(key[:], payload)
+
+		var src stateSource = &randv2.PCG{}
+		if kind == snapshotChaCha8 {
+			src = &randv2.ChaCha8{}
+		}
+		if err := src.UnmarshalBinary(payload[len(key):]); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidSnapshot, err)
+		}
+		source = newStateSource(src, key)
+
+	default:
+		return errInvalidSnapshot
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source = source
+	return nil
+}
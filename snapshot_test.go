@@ -0,0 +1,90 @@
+package d20
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRoller_Snapshot(t *testing.T) {
+	tests := []struct {
+		name   string
+		roller *Roller
+	}{
+		{"Seeded", NewRoller(42)},
+		{"PCG", NewRollerWithSource(NewPCGSource(1, 2))},
+		{"ChaCha8", NewRollerWithSource(NewChaCha8Source([32]byte{7}))},
+		{"Fork", NewRoller(42).Fork()},
+	}
+
+	rollMany := func(t *testing.T, roller *Roller) []int {
+		var rolls []int
+		for range 25 {
+			result, err := roller.Roll("4d6kh3+1d20!")
+			if err != nil {
+				t.Fatalf("Roll() error: %v", err)
+			}
+			rolls = append(rolls, result.DiceRolls...)
+		}
+		return rolls
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollMany(t, tt.roller) // Advance past the start of the stream
+
+			state, err := tt.roller.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error: %v", err)
+			}
+			want := rollMany(t, tt.roller)
+
+			var restored Roller
+			if err := restored.UnmarshalBinary(state); err != nil {
+				t.Fatalf("UnmarshalBinary() error: %v", err)
+			}
+			if got := rollMany(t, &restored); !slices.Equal(got, want) {
+				t.Error("restored roller should roll the same dice as the original")
+			}
+		})
+	}
+}
+
+func TestRoller_SnapshotErrors(t *testing.T) {
+	for name, roller := range map[string]*Roller{
+		"Crypto": NewRollerWithSource(NewCryptoSource()),
+		"Script": NewScriptedRoller(NewScript(1, 2, 3)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := roller.MarshalBinary(); !errors.Is(err, errSnapshotUnsupported) {
+				t.Errorf("MarshalBinary() error = %v, want %v", err, errSnapshotUnsupported)
+			}
+		})
+	}
+
+	for name, data := range map[string][]byte{
+		"Empty":         nil,
+		"Wrong version": {9, byte(snapshotSeeded)},
+		"Unknown kind":  {snapshotVersion, 99},
+		"Truncated":     {snapshotVersion, byte(snapshotSeeded), 0, 0, 0},
+		"Bad PCG state": {snapshotVersion, byte(snapshotPCG), 1, 2, 3},
+		"Too many draws": binary.BigEndian.AppendUint64(
+			binary.BigEndian.AppendUint64([]byte{snapshotVersion, byte(snapshotSeeded)}, 42), 1<<40),
+	} {
+		t.Run(name, func(t *testing.T) {
+			roller := NewRoller(42)
+			if err := roller.UnmarshalBinary(data); !errors.Is(err, errInvalidSnapshot) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, errInvalidSnapshot)
+			}
+		})
+	}
+
+	t.Run("Seeded source past the draw limit", func(t *testing.T) {
+		roller := NewRoller(42)
+		roller.source.(*seededSource).draws = maxSeededDraws + 1
+		if _, err := roller.MarshalBinary(); !errors.Is(err, errSnapshotUnsupported) {
+			t.Errorf("MarshalBinary() error = %v, want %v", err, errSnapshotUnsupported)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package d20
+
+import "strings"
+
+// ModifierType is the bonus type of a modifier, used by stacking policies
+// to decide which modifiers combine. Any lowercase name can be used as a type,
+// e.g. ModifierType("morale") for Pathfinder 1e.
+type ModifierType string
+
+const (
+	Untyped      ModifierType = ""             // Always stacks
+	Circumstance ModifierType = "circumstance" // Pathfinder circumstance bonus or penalty
+	Status       ModifierType = "status"       // Pathfinder status bonus or penalty
+	Item         ModifierType = "item"         // Pathfinder item bonus or penalty
+)
+
+// StackingPolicy decides which of a roll's modifiers apply. It returns the
+// modifiers that apply and those suppressed, each in their original order.
+// Dice modifiers are passed with their rolled values.
+type StackingPolicy func(modifiers []Modifier) (applied, suppressed []Modifier)
+
+// StackAll applies every modifier, as in D&D 5e. This is the default policy.
+func StackAll(modifiers []Modifier) (applied, suppressed []Modifier) {
+	return modifiers, nil
+}
+
+// HighestPerType applies Pathfinder 2e stacking: of the typed modifiers,
+// only the highest bonus and the worst penalty of each type apply.
+// Untyped bonuses and penalties always stack.
+func HighestPerType(modifiers []Modifier) (applied, suppressed []Modifier) {
+	return stackBest(modifiers, func(mod Modifier) (string, bool) {
+		return string(mod.Type), mod.Type != Untyped
+	})
+}
+
+// NoSameName applies the rule that effects with the same name don't stack:
+// of the modifiers sharing a reason, only the highest bonus and the worst
+// penalty apply, so two castings of Bless add one bonus.
+func NoSameName(modifiers []Modifier) (applied, suppressed []Modifier) {
+	return stackBest(modifiers, func(mod Modifier) (string, bool) {
+		return strings.ToLower(mod.Reason), true
+	})
+}
+
+// stackBest keeps the largest bonus and the largest penalty of each group
+// of modifiers. group returns a modifier's group and whether it is limited
+// at all; unlimited modifiers always apply. Ties keep the first modifier.
+func stackBest(modifiers []Modifier, group func(Modifier) (string, bool)) (applied, suppressed []Modifier) {
+	type groupKey struct {
+		name    string
+		penalty bool
+	}
+	best := make(map[groupKey]int)
+	for i, mod := range modifiers {
+		name, limited := group(mod)
+		if !limited {
+			continue
+		}
+		key := groupKey{name: name, penalty: mod.Value < 0}
+		current, seen := best[key]
+		if !seen || abs(mod.Value) > abs(modifiers[current].Value) {
+			best[key] = i
+		}
+	}
+
+	for i, mod := range modifiers {
+		name, limited := group(mod)
+		if limited && best[groupKey{name: name, penalty: mod.Value < 0}] != i {
+			suppressed = append(suppressed, mod)
+			continue
+		}
+		applied = append(applied, mod)
+	}
+	return applied, suppressed
+}
+
+// WithTypedModifier adds a modifier with a bonus type, such as a +1 status
+// bonus. Whether it stacks with other modifiers of the same type is decided
+// by the roll's StackingPolicy.
+//
+// Example:
+//
+//	roller.Dice(1, 20).
+//		WithStacking(d20.HighestPerType).
+//		WithTypedModifier("heroism", d20.Status, 1).
+//		WithTypedModifier("inspire courage", d20.Status, 1). // Suppressed
+//		Roll()
+func (rb *RollBuilder) WithTypedModifier(name string, kind ModifierType, value int) *RollBuilder {
+	rb.modifiers = append(rb.modifiers, NewTypedModifier(name, kind, value))
+	return rb
+}
+
+// ApplyModifiers adds modifiers that were created separately, such as a
+// typed dice modifier.
+//
+// Example:
+//
+//	bless, _ := d20.NewDiceModifier("bless", "1d4")
+//	roller.Dice(1, 20).ApplyModifiers(bless.OfType(d20.Status)).Roll()
+func (rb *RollBuilder) ApplyModifiers(modifiers ...Modifier) *RollBuilder {
+	rb.modifiers = append(rb.modifiers, modifiers...)
+	return rb
+}
+
+// WithStacking sets which of the roll's modifiers apply, replacing the
+// default of StackAll. Suppressed modifiers are listed in the outcome.
+//
+// Example:
+//
+//	actor.AttackRoll(roller).WithStacking(d20.HighestPerType).Roll()
+func (rb *RollBuilder) WithStacking(policy StackingPolicy) *RollBuilder {
+	rb.stacking = policy
+	return rb
+}
+
+// stack applies the roll's stacking policy to its rolled modifiers.
+func (rb *RollBuilder) stack(modifiers []Modifier) (applied, suppressed []Modifier) {
+	if rb.stacking == nil {
+		return StackAll(modifiers)
+	}
+	return rb.stacking(modifiers)
+}
+
+// suppressedNote lists the modifiers a stacking policy suppressed for the
+// detail string, e.g. "Not stacked: +1 inspire courage (status)".
+func suppressedNote(suppressed []Modifier) string {
+	strs := make([]string, len(suppressed))
+	for i, mod := range suppressed {
+		strs[i] = mod.String()
+	}
+	return "Not stacked: " + strings.Join(strs, ", ")
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,129 @@
+package d20
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStackingPolicies(t *testing.T) {
+	modifiers := []Modifier{
+		NewModifier("strength", 4),
+		NewTypedModifier("heroism", Status, 1),
+		NewTypedModifier("inspire courage", Status, 2),
+		NewTypedModifier("frightened", Status, -1),
+		NewTypedModifier("sickened", Status, -2),
+		NewTypedModifier("striking rune", Item, 1),
+		NewModifier("bless", 1),
+		NewModifier("bless", 1),
+		NewModifier("off-guard", -2),
+	}
+
+	tests := []struct {
+		name           string
+		policy         StackingPolicy
+		wantSuppressed []string
+	}{
+		{name: "Stack all", policy: StackAll, wantSuppressed: nil},
+		{name: "Highest per type", policy: HighestPerType, wantSuppressed: []string{"heroism", "frightened"}},
+		{name: "No same name", policy: NoSameName, wantSuppressed: []string{"bless"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied, suppressed := tt.policy(modifiers)
+			var reasons []string
+			for _, mod := range suppressed {
+				reasons = append(reasons, mod.Reason)
+			}
+			if !slices.Equal(reasons, tt.wantSuppressed) {
+				t.Errorf("suppressed = %v, want %v", reasons, tt.wantSuppressed)
+			}
+			if len(applied)+len(suppressed) != len(modifiers) {
+				t.Errorf("applied %d and suppressed %d of %d modifiers", len(applied), len(suppressed), len(modifiers))
+			}
+		})
+	}
+}
+
+func TestRollBuilder_WithStacking(t *testing.T) {
+	roller := NewScriptedRoller(NewScript(10, 10, 3))
+
+	t.Run("Suppressed modifiers are listed", func(t *testing.T) {
+		result, err := roller.Dice(1, 20).
+			WithStacking(HighestPerType).
+			WithModifier("strength", 4).
+			WithTypedModifier("heroism", Status, 1).
+			WithTypedModifier("Inspire Courage", Status, 2).
+			Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Value != 16 {
+			t.Errorf("Value = %d, want 16", result.Value)
+		}
+		if len(result.Suppressed) != 1 || result.Suppressed[0].Reason != "heroism" {
+			t.Errorf("Suppressed = %v, want heroism", result.Suppressed)
+		}
+		want := "Rolled 1d20... 10; +4 strength, +2 inspire courage (status); *Result: 16*; Not stacked: +1 heroism (status)"
+		if result.Detail != want {
+			t.Errorf("Detail = %q, want %q", result.Detail, want)
+		}
+	})
+
+	t.Run("Typed dice modifiers compare rolled values", func(t *testing.T) {
+		bless, err := NewDiceModifier("bless", "1d4")
+		if err != nil {
+			t.Fatalf("NewDiceModifier() error: %v", err)
+		}
+		result, err := roller.Dice(1, 20).
+			WithStacking(HighestPerType).
+			WithTypedModifier("heroism", Status, 1).
+			ApplyModifiers(bless.OfType(Status)).
+			Roll()
+		if err != nil {
+			t.Fatalf("Roll() error: %v", err)
+		}
+		if result.Value != 13 || !strings.Contains(result.Detail, "Not stacked: +1 heroism (status)") {
+			t.Errorf("Value = %d, Detail = %q; want bless to replace heroism", result.Value, result.Detail)
+		}
+	})
+
+	t.Run("Distribution", func(t *testing.T) {
+		dist, err := NewRoller(42).Dice(1, 20).
+			WithStacking(HighestPerType).
+			WithTypedModifier("heroism", Status, 1).
+			WithTypedModifier("inspire courage", Status, 2).
+			Distribution()
+		if err != nil {
+			t.Fatalf("Distribution() error: %v", err)
+		}
+		if dist.Min() != 3 {
+			t.Errorf("Min() = %d, want 3", dist.Min())
+		}
+
+		_, err = NewRoller(42).Dice(1, 20).WithStacking(HighestPerType).WithDiceModifier("bless", "1d4").Distribution()
+		if err == nil {
+			t.Error("Distribution() should reject stacking rules with dice modifiers")
+		}
+	})
+}
+
+func TestActor_TypedCombatModifiers(t *testing.T) {
+	actor, err := NewActor("champion").
+		WithHP(20).
+		WithTypedCombatModifier("+1 weapon", Item, 1).
+		WithTypedCombatModifier("bracers", Item, 2).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	actor.AddTypedCombatModifier("heroism", Status, 1)
+
+	roller := NewScriptedRoller(NewScript(10, 10))
+	stacked, _ := actor.AttackRoll(roller).Roll()
+	limited, _ := actor.AttackRoll(roller).WithStacking(HighestPerType).Roll()
+	if stacked.Value != 14 || limited.Value != 13 {
+		t.Errorf("Values = %d, %d; want 14 stacked and 13 with item bonuses limited", stacked.Value, limited.Value)
+	}
+}